@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+var showAll = flag.Bool("all", false, "print the metrics of every function, against their thresholds, and add them to the json output")
+
+// allMetric is a function metric printed by -all, with its threshold, if
+// it has one that is turned on.
+type allMetric struct {
+	name      string
+	value     func(*lint.FunctionMetrics) int
+	threshold func(*lint.Options) int
+}
+
+var allMetrics = []allMetric{
+	{"statements", func(m *lint.FunctionMetrics) int { return m.Statements }, func(o *lint.Options) int { return o.StatementThreshold }},
+	{"lines", func(m *lint.FunctionMetrics) int { return m.Lines }, func(o *lint.Options) int { return o.LineThreshold }},
+	{"params", func(m *lint.FunctionMetrics) int { return m.Params }, func(o *lint.Options) int { return o.ParamThreshold }},
+	{"results", func(m *lint.FunctionMetrics) int { return m.Results }, func(o *lint.Options) int { return o.ResultThreshold }},
+	{"returns", func(m *lint.FunctionMetrics) int { return m.Returns }, func(o *lint.Options) int { return o.ReturnThreshold }},
+	{"locals", func(m *lint.FunctionMetrics) int { return m.Locals }, func(o *lint.Options) int { return o.LocalThreshold }},
+	{"if chain", func(m *lint.FunctionMetrics) int { return m.IfChain }, func(o *lint.Options) int { return o.IfChainThreshold }},
+	{"nesting", func(m *lint.FunctionMetrics) int { return m.Nesting }, nil},
+	{"cognitive", func(m *lint.FunctionMetrics) int { return m.Cognitive }, func(o *lint.Options) int { return o.CognitiveThreshold }},
+	{"cyclomatic", func(m *lint.FunctionMetrics) int { return m.Cyclomatic }, nil},
+}
+
+// printAll prints a line with the metrics of every function, each with its
+// threshold after a slash.
+func printAll(w io.Writer, summary *lint.Summary, opts lint.Options) {
+	for _, m := range summary.Functions {
+		var parts []string
+		for _, metric := range allMetrics {
+			part := fmt.Sprintf("%s %d", metric.name, metric.value(m))
+			if metric.threshold != nil && metric.threshold(&opts) > 0 {
+				part += fmt.Sprintf("/%d", metric.threshold(&opts))
+			}
+			parts = append(parts, part)
+		}
+		fmt.Fprintf(w, "%s:\t%s: %s\n", m.Position, m.Function, strings.Join(parts, ", "))
+	}
+}
@@ -0,0 +1,155 @@
+// Package analyzer exposes the splint checks as a go/analysis Analyzer, so
+// they can be run by go vet, golangci-lint, or any other analysis driver.
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/agflow/splint/lint"
+)
+
+var opts = lint.DefaultOptions()
+
+// enable and disable are the comma-separated checks of the -enable and
+// -disable flags.
+var enable, disable string
+
+// testThresholds is the list of thresholds for test files of the
+// -testthresholds flag.
+var testThresholds string
+
+// ignoreFuncs and onlyFuncs are the regexps of the -ignorefuncs and
+// -onlyfuncs flags.
+var ignoreFuncs, onlyFuncs regexpFlag
+
+// regexpFlag is a flag holding a compiled regexp.
+type regexpFlag struct{ re *regexp.Regexp }
+
+func (f *regexpFlag) String() string {
+	if f.re == nil {
+		return ""
+	}
+	return f.re.String()
+}
+
+func (f *regexpFlag) Set(value string) (err error) {
+	f.re, err = regexp.Compile(value)
+	return err
+}
+
+// Analyzer reports the issues the splint checks find in the files of a
+// package, like functions, files and types that grew too large or too
+// complex.
+var Analyzer = &analysis.Analyzer{
+	Name: "splint",
+	Doc: `find code that is too long, too complex, or duplicated
+
+splint reports functions with too many statements, lines, params, results,
+returns or locals, long if/else chains, high cognitive complexity, empty
+and unreachable code, duplicate functions, and files, structs, interfaces
+and types that grew too large.  Thresholds are set with the flags below,
+and checks are picked with -enable and -disable.`,
+	Run: run,
+}
+
+//splint:ignore init-length,statement-count a flag for each option
+func init() {
+	Analyzer.Flags.IntVar(&opts.StatementThreshold, "statements", opts.StatementThreshold, "function statement count threshold")
+	Analyzer.Flags.IntVar(&opts.LineThreshold, "lines", opts.LineThreshold, "function line count threshold (0 disables the check)")
+	Analyzer.Flags.IntVar(&opts.InitThreshold, "init", opts.InitThreshold, "init function statement count threshold")
+	Analyzer.Flags.IntVar(&opts.ParamThreshold, "params", opts.ParamThreshold, "parameter list length threshold")
+	Analyzer.Flags.IntVar(&opts.VariadicThreshold, "variadic", opts.VariadicThreshold, "threshold of params besides a variadic one")
+	Analyzer.Flags.IntVar(&opts.ResultThreshold, "results", opts.ResultThreshold, "result list length threshold")
+	Analyzer.Flags.IntVar(&opts.ReturnThreshold, "returns", opts.ReturnThreshold, "return statement count threshold")
+	Analyzer.Flags.IntVar(&opts.LocalThreshold, "locals", opts.LocalThreshold, "local variable count threshold")
+	Analyzer.Flags.IntVar(&opts.IfChainThreshold, "ifchain", opts.IfChainThreshold, "if/else chain length threshold")
+	Analyzer.Flags.IntVar(&opts.IfBodyThreshold, "ifbody", opts.IfBodyThreshold, "if body statement count threshold")
+	Analyzer.Flags.IntVar(&opts.SwitchCaseThreshold, "cases", opts.SwitchCaseThreshold, "switch case count threshold")
+	Analyzer.Flags.IntVar(&opts.TypeSwitchCaseThreshold, "typecases", opts.TypeSwitchCaseThreshold, "type switch case count threshold")
+	Analyzer.Flags.IntVar(&opts.CaseBodyThreshold, "casebody", opts.CaseBodyThreshold, "case body statement count threshold")
+	Analyzer.Flags.IntVar(&opts.LabelThreshold, "labels", opts.LabelThreshold, "goto, label and labeled break or continue count threshold")
+	Analyzer.Flags.IntVar(&opts.ConditionThreshold, "cond", opts.ConditionThreshold, "threshold of boolean operators, and of comparisons, in a condition")
+	Analyzer.Flags.IntVar(&opts.GuardThreshold, "guard", opts.GuardThreshold, "percentage of the statements of a function inside a single if above which a guard clause is suggested")
+	Analyzer.Flags.IntVar(&opts.ChainThreshold, "chain", opts.ChainThreshold, "threshold of calls chained in an expression")
+	Analyzer.Flags.IntVar(&opts.GoroutineThreshold, "goroutines", opts.GoroutineThreshold, "go statement count threshold")
+	Analyzer.Flags.IntVar(&opts.FanOutThreshold, "fanout", opts.FanOutThreshold, "threshold of distinct functions and methods a function calls")
+	Analyzer.Flags.IntVar(&opts.ClosureDepthThreshold, "closuredepth", opts.ClosureDepthThreshold, "threshold of function literals a function literal is nested in, counting itself")
+	Analyzer.Flags.IntVar(&opts.DiscardedErrorThreshold, "discardederrors", opts.DiscardedErrorThreshold, "discarded error count threshold")
+	Analyzer.Flags.IntVar(&opts.CognitiveThreshold, "cognitive", opts.CognitiveThreshold, "cognitive complexity threshold")
+	Analyzer.Flags.IntVar(&opts.DuplicateThreshold, "dup", opts.DuplicateThreshold, "statement count from which functions are compared for duplicates")
+	Analyzer.Flags.IntVar(&opts.DuplicateSimilarity, "dupsimilarity", opts.DuplicateSimilarity, "percentage of statements functions share from which they are near duplicates, 100 for exact copies only")
+	Analyzer.Flags.IntVar(&opts.FileLineThreshold, "filelines", opts.FileLineThreshold, "file line count threshold (0 disables the check)")
+	Analyzer.Flags.IntVar(&opts.FileFunctionThreshold, "filefuncs", opts.FileFunctionThreshold, "functions per file threshold (0 disables the check)")
+	Analyzer.Flags.IntVar(&opts.StructFieldThreshold, "fields", opts.StructFieldThreshold, "struct field count threshold")
+	Analyzer.Flags.IntVar(&opts.InterfaceMethodThreshold, "ifacemethods", opts.InterfaceMethodThreshold, "interface method count threshold")
+	Analyzer.Flags.BoolVar(&opts.MagicNumbers, "magic", opts.MagicNumbers, "report functions using magic numbers")
+	Analyzer.Flags.IntVar(&opts.MagicNumberThreshold, "magicmax", opts.MagicNumberThreshold, "magic number count threshold")
+	Analyzer.Flags.BoolVar(&opts.Panics, "panics", opts.Panics, "report functions using panic and recover")
+	Analyzer.Flags.IntVar(&opts.PanicThreshold, "panicsmax", opts.PanicThreshold, "panic and recover call count threshold")
+	Analyzer.Flags.BoolVar(&opts.SwitchDefaults, "switchdefault", opts.SwitchDefaults, "report switches without a default clause")
+	Analyzer.Flags.IntVar(&opts.SwitchDefaultThreshold, "switchdefaultcases", opts.SwitchDefaultThreshold, "case count threshold of switches without a default clause")
+	Analyzer.Flags.IntVar(&opts.MaxLineLength, "maxlen", opts.MaxLineLength, "report lines longer than N columns")
+	Analyzer.Flags.IntVar(&opts.CommentThreshold, "comments", opts.CommentThreshold, "report functions with more than N statements and few comments")
+	Analyzer.Flags.Float64Var(&opts.CommentRatio, "commentratio", opts.CommentRatio, "comment lines per statement below which -comments reports a function")
+	Analyzer.Flags.Float64Var(&opts.HalsteadVolume, "halsteadvolume", opts.HalsteadVolume, "Halstead volume threshold (0 disables it)")
+	Analyzer.Flags.Float64Var(&opts.HalsteadDifficulty, "halsteaddifficulty", opts.HalsteadDifficulty, "Halstead difficulty threshold (0 disables it)")
+	Analyzer.Flags.Float64Var(&opts.HalsteadEffort, "halsteadeffort", opts.HalsteadEffort, "Halstead effort threshold (0 disables it)")
+	Analyzer.Flags.Float64Var(&opts.MinMaintainability, "minmi", opts.MinMaintainability, "report functions and files with a maintainability index below N")
+	Analyzer.Flags.IntVar(&opts.TabWidth, "tabwidth", opts.TabWidth, "number of columns of a tab")
+	Analyzer.Flags.IntVar(&opts.MethodThreshold, "methods", opts.MethodThreshold, "methods per type threshold")
+	Analyzer.Flags.IntVar(&opts.ImportThreshold, "imports", opts.ImportThreshold, "imports per file threshold")
+	Analyzer.Flags.IntVar(&opts.MarkerThreshold, "todos", opts.MarkerThreshold, "report files with more than N TODO, FIXME, HACK or XXX markers")
+	Analyzer.Flags.IntVar(&opts.GlobalThreshold, "globals", opts.GlobalThreshold, "package variables per file threshold")
+	Analyzer.Flags.StringVar(&enable, "enable", "", "run only these comma-separated checks, by name or ID")
+	Analyzer.Flags.StringVar(&disable, "disable", "", "turn off these comma-separated checks, by name or ID")
+	Analyzer.Flags.Var(&ignoreFuncs, "ignorefuncs", "skip the functions whose name, or Type.Method name, matches this regexp")
+	Analyzer.Flags.Var(&onlyFuncs, "onlyfuncs", "only check the functions whose name, or Type.Method name, matches this regexp")
+	Analyzer.Flags.StringVar(&testThresholds, "testthresholds", "", "thresholds for test files, like statements=80,lines=300")
+}
+
+// checkOptions applies -enable, -disable, -testthresholds, -ignorefuncs
+// and -onlyfuncs to the options.
+func checkOptions() (lint.Options, error) {
+	o := opts
+	o.Disabled = nil
+	o.IgnoreFuncs, o.OnlyFuncs = ignoreFuncs.re, onlyFuncs.re
+	if testThresholds != "" {
+		set, err := lint.ParseThresholds(testThresholds)
+		if err != nil {
+			return o, err
+		}
+		o.TestThresholds = set
+	}
+	if enable != "" {
+		if err := o.Enable(strings.Split(enable, ",")...); err != nil {
+			return o, err
+		}
+	}
+	if disable != "" {
+		if err := o.Disable(strings.Split(disable, ",")...); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	opts, err := checkOptions()
+	if err != nil {
+		return nil, err
+	}
+	summary := new(lint.Summary)
+	summary.Warn = func(o *lint.Offender) {
+		pass.Reportf(o.Pos, "%s", o.Message())
+	}
+	for _, f := range pass.Files {
+		filename := pass.Fset.Position(f.Pos()).Filename
+		lint.NewParser(filename, summary, opts).CheckTypes(pass.Fset, f, pass.TypesInfo)
+	}
+	summary.CheckMethods(opts)
+	summary.CheckDuplicates(opts)
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/agflow/splint/lint"
+)
+
+var badgeGrade = flag.Bool("grade", false, "with splint badge, show a letter grade from the issues per function instead of the number of issues")
+
+// grades are the letter grades of splint badge -grade, by the most issues
+// per function they allow, with their colors.
+var grades = []struct {
+	Letter     string
+	MaxPerFunc float64
+	Color      string
+}{
+	{"A", 0.02, "#4c1"},
+	{"B", 0.05, "#97ca00"},
+	{"C", 0.1, "#dfb317"},
+	{"D", 0.2, "#fe7d37"},
+	{"F", math.Inf(1), "#e05d44"},
+}
+
+// fewIssues is the number of issues below which the badge is yellow
+// rather than red.
+const fewIssues = 10
+
+// badgeCharWidth is the average width of a character of the badge font,
+// which is all the badge has to go by to size its text.
+const badgeCharWidth = 7
+
+// badgeMargin is the space around the text of each half of the badge.
+const badgeMargin = 10
+
+// badgeData is what the badge template is executed on.
+type badgeData struct {
+	Label, Message, Color      string
+	LabelWidth, MessageWidth   int
+	LabelCenter, MessageCenter int
+}
+
+var badgeTemplate = template.Must(template.New("badge").Parse(`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="20" role="img" aria-label="{{.Label}}: {{.Message}}">
+<title>{{.Label}}: {{.Message}}</title>
+<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>
+<clipPath id="r"><rect width="{{.Width}}" height="20" rx="3" fill="#fff"/></clipPath>
+<g clip-path="url(#r)"><rect width="{{.LabelWidth}}" height="20" fill="#555"/><rect x="{{.LabelWidth}}" width="{{.MessageWidth}}" height="20" fill="{{.Color}}"/><rect width="{{.Width}}" height="20" fill="url(#s)"/></g>
+<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">
+<text x="{{.LabelCenter}}" y="15" fill="#010101" fill-opacity=".3">{{.Label}}</text><text x="{{.LabelCenter}}" y="14">{{.Label}}</text>
+<text x="{{.MessageCenter}}" y="15" fill="#010101" fill-opacity=".3">{{.Message}}</text><text x="{{.MessageCenter}}" y="14">{{.Message}}</text>
+</g>
+</svg>
+`))
+
+// Width is the width of the whole badge.
+func (b badgeData) Width() int {
+	return b.LabelWidth + b.MessageWidth
+}
+
+// newBadge lays out a badge, sizing each half to its text.
+func newBadge(label, message, color string) badgeData {
+	b := badgeData{Label: label, Message: message, Color: color}
+	b.LabelWidth = utf8.RuneCountInString(label)*badgeCharWidth + badgeMargin
+	b.MessageWidth = utf8.RuneCountInString(message)*badgeCharWidth + badgeMargin
+	b.LabelCenter = b.LabelWidth / 2
+	b.MessageCenter = b.LabelWidth + b.MessageWidth/2
+	return b
+}
+
+// countIssues returns the number of issues of the checks turned on, and
+// the number of functions analyzed.
+func countIssues(summary *lint.Summary) (issues, functions int) {
+	for _, section := range summary.Sections() {
+		if !turnedOff(section.Check) {
+			issues += len(section.Offenders)
+		}
+	}
+	for _, p := range summary.Packages {
+		functions += p.Functions
+	}
+	return issues, functions
+}
+
+// summaryBadge makes the badge of a summary: the number of issues, green
+// when there are none, yellow when there are few, or the letter grade of
+// the issues per function.
+func summaryBadge(summary *lint.Summary) badgeData {
+	issues, functions := countIssues(summary)
+	if *badgeGrade {
+		perFunc := float64(issues) / float64(max(functions, 1))
+		for _, g := range grades {
+			if perFunc <= g.MaxPerFunc {
+				return newBadge("complexity", g.Letter, g.Color)
+			}
+		}
+	}
+	switch {
+	case issues == 0:
+		return newBadge("complexity", "no issues", "#4c1")
+	case issues == 1:
+		return newBadge("complexity", "1 issue", "#dfb317")
+	case issues < fewIssues:
+		return newBadge("complexity", fmt.Sprintf("%d issues", issues), "#dfb317")
+	}
+	return newBadge("complexity", fmt.Sprintf("%d issues", issues), "#e05d44")
+}
+
+// badge analyzes args and writes the badge of the results as SVG, to -o
+// or stdout, returning whether it failed.
+func badge(args []string, opts lint.Options) bool {
+	summary, err := analyze(args, opts)
+	if summary == nil {
+		fmt.Println(err)
+		return true
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+	out, err := output()
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	defer out.Close()
+	if err := writeBadge(out, summary); err != nil {
+		fmt.Println(err)
+		return true
+	}
+	return false
+}
+
+func writeBadge(w io.Writer, summary *lint.Summary) error {
+	return badgeTemplate.Execute(w, summaryBadge(summary))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agflow/splint/lint"
+)
+
+var blameMode = flag.Bool("blame", false, "annotate every issue with the author and date of the last change to its function, from git blame")
+
+// blameAll annotates the offenders of a summary with the last change to
+// their function, blaming each function once.  Offenders in files git
+// can't blame are left as they are, and the first error is returned.
+func blameAll(summary *lint.Summary) error {
+	blames := make(map[string]*lint.Blame)
+	var first error
+	for _, section := range summary.Sections() {
+		for _, o := range section.Offenders {
+			key := fmt.Sprintf("%s:%d,%d", o.Position.Filename, o.Span.First, o.Span.Last)
+			b, ok := blames[key]
+			if !ok {
+				var err error
+				b, err = blame(o.Position.Filename, o.Span)
+				if err != nil && first == nil {
+					first = err
+				}
+				blames[key] = b
+			}
+			o.Blame = b
+		}
+	}
+	return first
+}
+
+// blame returns the most recent change to a range of lines of a file.
+func blame(filename string, lines lint.LineRange) (*lint.Blame, error) {
+	out, err := git("blame", "--porcelain", "-L", fmt.Sprintf("%d,%d", lines.First, lines.Last), "--", filename)
+	if err != nil {
+		return nil, err
+	}
+	return parseBlame(out), nil
+}
+
+// parseBlame finds the commit with the latest commit date in the output
+// of git blame --porcelain, where the header of every line names its
+// commit, followed by the details of the commit the first time it
+// appears.
+func parseBlame(out string) *lint.Blame {
+	commits := make(map[string]*lint.Blame)
+	var latest, b *lint.Blame
+	for _, line := range strings.Split(out, "\n") {
+		key, value, _ := strings.Cut(line, " ")
+		switch {
+		case strings.HasPrefix(line, "\t"):
+			continue
+		case len(key) == 40 && commits[key] == nil:
+			b = &lint.Blame{Commit: key}
+			commits[key] = b
+		case len(key) == 40:
+			b = commits[key]
+		case b == nil:
+			continue
+		case key == "author":
+			b.Author = value
+		case key == "author-mail":
+			b.Email = strings.Trim(value, "<>")
+		case key == "committer-time":
+			sec, _ := strconv.ParseInt(value, 10, 64)
+			b.Date = time.Unix(sec, 0).UTC()
+		}
+		if b != nil && (latest == nil || b.Date.After(latest.Date)) {
+			latest = b
+		}
+	}
+	return latest
+}
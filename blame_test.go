@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/agflow/splint/lint"
+)
+
+func TestParseBlame(t *testing.T) {
+	older := "1111111111111111111111111111111111111111"
+	newer := "2222222222222222222222222222222222222222"
+	out := older + " 1 1 2\n" +
+		"author Ann\nauthor-mail <ann@example.com>\nauthor-time 1700000000\n" +
+		"committer Ann\ncommitter-time 1700000000\nsummary first\nfilename a.go\n" +
+		"\tfunc f() {\n" +
+		newer + " 2 2 1\n" +
+		"author Bob\nauthor-mail <bob@example.com>\nauthor-time 1600000000\n" +
+		"committer Cy\ncommitter-time 1710000000\nsummary second\nfilename a.go\n" +
+		"\tauthor Mallory\n" +
+		older + " 3 3\n" +
+		"\t}\n"
+	want := &lint.Blame{Commit: newer, Author: "Bob", Email: "bob@example.com", Date: time.Unix(1710000000, 0).UTC()}
+	if got := parseBlame(out); got == nil || *got != *want {
+		t.Errorf("parseBlame = %+v, want %+v", got, want)
+	}
+	if got := parseBlame(""); got != nil {
+		t.Errorf("parseBlame of no output = %+v, want nil", got)
+	}
+}
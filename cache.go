@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/agflow/splint/lint"
+)
+
+var useCache = flag.Bool("cache", false, "reuse the results of files that haven't changed since the last run")
+var cacheDir = flag.String("cache-dir", "", "keep the cache in `dir` (default splint under the user cache directory)")
+var debug = flag.Bool("debug", false, "print debugging information, like cache statistics, to stderr")
+
+// openCache sets up the result cache, if asked for.
+func openCache(opts *lint.Options) error {
+	if !*useCache {
+		return nil
+	}
+	cache, err := lint.OpenCache(*cacheDir)
+	if err != nil {
+		return fmt.Errorf("error opening cache: %s", err)
+	}
+	opts.Cache = cache
+	return nil
+}
+
+// printDebug prints what went on during the analysis, with -debug.
+func printDebug(opts lint.Options) {
+	if !*debug {
+		return
+	}
+	if opts.Cache == nil {
+		fmt.Fprintln(os.Stderr, "cache: off")
+		return
+	}
+	hits, misses := opts.Cache.Stats()
+	fmt.Fprintf(os.Stderr, "cache: %d hits, %d misses (%s)\n", hits, misses, opts.Cache.Dir())
+}
@@ -0,0 +1,15 @@
+// splint-vet runs the splint checks as a go/analysis driver, so they can be
+// used standalone or through go vet:
+//
+// go vet -vettool=$(which splint-vet) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/agflow/splint/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/agflow/splint/lint"
+)
+
+// describe names an offender read back from json, which has no message.
+func describe(o *lint.Offender) string {
+	name := o.Function
+	if o.Type != "" {
+		name = o.Type
+	}
+	if name == "" {
+		name = o.Filename
+	}
+	return fmt.Sprintf("%s:\t%s %s: %d", o.Position, o.Check, name, o.Count)
+}
+
+// compare prints the differences between two json summaries, returning
+// whether new issues appeared or the summaries could not be read.
+func compare(w io.Writer, before, after string) bool {
+	old, err := lint.ReadSummary(before)
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	cur, err := lint.ReadSummary(after)
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	c := lint.Compare(old, cur)
+	if err := writeComparison(w, c); err != nil {
+		fmt.Println(err)
+		return true
+	}
+	return len(c.New) > 0
+}
+
+func writeComparison(w io.Writer, c *lint.Comparison) error {
+	if *outputFormat == "json" {
+		data, err := json.MarshalIndent(c, "", "\t")
+		if err == nil {
+			_, err = fmt.Fprintln(w, string(data))
+		}
+		return err
+	}
+	printComparison(w, c)
+	return nil
+}
+
+// printComparison prints the new and fixed issues, the issues whose count
+// changed, and the number of issues of each check that changed.
+func printComparison(w io.Writer, c *lint.Comparison) {
+	for _, o := range c.New {
+		fmt.Fprintf(w, "new: %s\n", describe(o))
+	}
+	for _, o := range c.Fixed {
+		fmt.Fprintf(w, "fixed: %s\n", describe(o))
+	}
+	for _, ch := range c.Unchanged {
+		if d := ch.Delta(); d != 0 {
+			fmt.Fprintf(w, "changed: %s (%+d)\n", describe(ch.New), d)
+		}
+	}
+	fmt.Fprintln(w)
+	for _, check := range lint.Checks() {
+		if n := c.Checks[check]; n[0] != n[1] {
+			fmt.Fprintf(w, "%s: %d -> %d (%+d)\n", check, n[0], n[1], n[1]-n[0])
+		}
+	}
+	printPackageDeltas(w, c)
+	fmt.Fprintf(w, "%d new, %d fixed, %d unchanged issues\n", len(c.New), len(c.Fixed), len(c.Unchanged))
+}
+
+// printPackageDeltas prints how the functions of each package changed.
+func printPackageDeltas(w io.Writer, c *lint.Comparison) {
+	var paths []string
+	for path := range c.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		old, cur := c.Packages[path][0], c.Packages[path][1]
+		if old == nil {
+			old = new(lint.PackageSummary)
+		}
+		if cur == nil {
+			cur = new(lint.PackageSummary)
+		}
+		if old.Functions == cur.Functions && old.Statements == cur.Statements && old.MaxStatements == cur.MaxStatements {
+			continue
+		}
+		fmt.Fprintf(w, "%s: %d -> %d functions, %.1f -> %.1f avg statements, %d -> %d max statements\n",
+			path, old.Functions, cur.Functions, old.AvgStatements, cur.AvgStatements, old.MaxStatements, cur.MaxStatements)
+	}
+}
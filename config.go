@@ -0,0 +1,243 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+const defaultConfigFile = ".splint.json"
+
+var configFile = flag.String("config", defaultConfigFile, "read settings from json config `file`")
+
+// config is the contents of a splint config file.  Settings given on the
+// command line are added to the ones in the file.
+type config struct {
+	// Exclude and ExcludeRegexp list the paths to skip, as globs and
+	// as regular expressions.
+	Exclude       []string
+	ExcludeRegexp []string
+
+	// SkipDirs, if set, replaces the default names of the directories
+	// to skip, unless -skip-dirs is given.
+	SkipDirs []string
+
+	// Severity maps check names to their severity.
+	Severity map[string]string
+
+	// TestThresholds maps check names, like statements, to their
+	// threshold in test files.
+	TestThresholds map[string]int
+
+	// Enable lists the only checks to run, and Disable the checks to
+	// turn off, by name or ID.
+	Enable  []string
+	Disable []string
+}
+
+// stringsFlag is a flag that can be repeated, collecting every value.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+var skipDirs = flag.String("skip-dirs", strings.Join(lint.DefaultOptions().SkipDirs, ","), "comma-separated directory `names` skipped when walking patterns like ./... (empty to skip none)")
+
+var excludeGlobs stringsFlag
+var excludeRegexps stringsFlag
+var severities stringsFlag
+var testThresholds stringsFlag
+var enableChecks stringsFlag
+var disableChecks stringsFlag
+
+// disabled holds the checks turned off by -enable, -disable and the config
+// file, which the summary leaves out.
+var disabled map[string]bool
+
+func init() {
+	flag.Var(&enableChecks, "enable", "run only the comma-separated `checks`, by name or ID (repeatable)")
+	flag.Var(&disableChecks, "disable", "turn off the comma-separated `checks`, by name or ID (repeatable)")
+	flag.Var(&testThresholds, "test-threshold", "set thresholds for test files, like `statements=80,lines=300` (repeatable)")
+	flag.Var(&severities, "severity", "set the severity of a check with `check=level`, where level is error, warning or info (repeatable)")
+	flag.Var(&excludeGlobs, "exclude", "skip files matching glob `pattern`, where ** matches any directories (repeatable)")
+	flag.Var(&excludeRegexps, "exclude-re", "skip files matching `regexp` (repeatable)")
+}
+
+// loadConfig reads the config file, which may only be missing when it was
+// not asked for explicitly.
+func loadConfig() (*config, error) {
+	cfg := new(config)
+	data, err := os.ReadFile(*configFile)
+	if errors.Is(err, fs.ErrNotExist) && *configFile == defaultConfigFile {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("error reading config %s: %s", *configFile, err)
+	}
+	return cfg, nil
+}
+
+// apply sets up the options that come from both the config file and the
+// command line.
+func (cfg *config) apply(opts *lint.Options) error {
+	if err := cfg.applyExcludes(opts); err != nil {
+		return err
+	}
+	if err := applyFuncs(opts); err != nil {
+		return err
+	}
+	if err := cfg.applySeverities(opts); err != nil {
+		return err
+	}
+	if err := cfg.applyTestThresholds(opts); err != nil {
+		return err
+	}
+	return cfg.applyChecks(opts)
+}
+
+// applyFuncs sets up the functions to skip, or the only ones to check.
+func applyFuncs(opts *lint.Options) (err error) {
+	if *ignoreFuncs != "" {
+		if opts.IgnoreFuncs, err = regexp.Compile(*ignoreFuncs); err != nil {
+			return fmt.Errorf("bad -ignore-funcs regexp: %s", err)
+		}
+	}
+	if *onlyFuncs != "" {
+		if opts.OnlyFuncs, err = regexp.Compile(*onlyFuncs); err != nil {
+			return fmt.Errorf("bad -only-funcs regexp: %s", err)
+		}
+	}
+	return nil
+}
+
+// applyExcludes sets up the files and directories to skip.
+func (cfg *config) applyExcludes(opts *lint.Options) error {
+	for _, glob := range append(cfg.Exclude, excludeGlobs...) {
+		re, err := lint.CompileGlob(glob)
+		if err != nil {
+			return fmt.Errorf("bad exclude pattern %q: %s", glob, err)
+		}
+		opts.Exclude = append(opts.Exclude, re)
+	}
+	for _, expr := range append(cfg.ExcludeRegexp, excludeRegexps...) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("bad exclude regexp %q: %s", expr, err)
+		}
+		opts.Exclude = append(opts.Exclude, re)
+	}
+	if cfg.SkipDirs != nil && !flagGiven("skip-dirs") {
+		opts.SkipDirs = cfg.SkipDirs
+	}
+	return nil
+}
+
+// commaList splits a comma-separated list, which may be empty.
+func commaList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// flagGiven reports whether a flag was set on the command line.
+func flagGiven(name string) (given bool) {
+	flag.Visit(func(f *flag.Flag) { given = given || f.Name == name })
+	return given
+}
+
+// checkList splits lists of comma-separated checks.
+func checkList(lists []string) []string {
+	var names []string
+	for _, list := range lists {
+		names = append(names, strings.Split(list, ",")...)
+	}
+	return names
+}
+
+// applyChecks turns off the checks left out by the enabled ones, and the
+// disabled ones, including those of -b and -skip-statements.
+func (cfg *config) applyChecks(opts *lint.Options) error {
+	if enable := append(cfg.Enable, checkList(enableChecks)...); len(enable) > 0 {
+		if err := opts.Enable(enable...); err != nil {
+			return err
+		}
+	}
+	disable := append(cfg.Disable, checkList(disableChecks)...)
+	if *skipBoolParamCheck {
+		disable = append(disable, lint.CheckBoolParam)
+	}
+	if *skipStatementCheck {
+		disable = append(disable, lint.CheckStatementCount)
+	}
+	err := opts.Disable(disable...)
+	disabled = opts.Disabled
+	return err
+}
+
+// applyTestThresholds sets the thresholds for test files of the config
+// file, then those of -test-threshold.
+func (cfg *config) applyTestThresholds(opts *lint.Options) error {
+	var lists []string
+	for name, n := range cfg.TestThresholds {
+		lists = append(lists, fmt.Sprintf("%s=%d", name, n))
+	}
+	for _, list := range append(lists, testThresholds...) {
+		set, err := lint.ParseThresholds(list)
+		if err != nil {
+			return fmt.Errorf("bad test threshold: %s", err)
+		}
+		if opts.TestThresholds == nil {
+			opts.TestThresholds = make(map[string]int)
+		}
+		for check, n := range set {
+			opts.TestThresholds[check] = n
+		}
+	}
+	return nil
+}
+
+func (cfg *config) applySeverities(opts *lint.Options) error {
+	levels := make(map[string]string)
+	for check, level := range cfg.Severity {
+		levels[check] = level
+	}
+	for _, s := range severities {
+		check, level, ok := strings.Cut(s, "=")
+		if !ok {
+			return fmt.Errorf("bad severity %q, want check=level", s)
+		}
+		levels[check] = level
+	}
+	for name, level := range levels {
+		check, ok := lint.LookupCheck(name)
+		if !ok {
+			return fmt.Errorf("unknown check %q", name)
+		}
+		sev, err := lint.ParseSeverity(level)
+		if err != nil {
+			return err
+		}
+		if opts.Severities == nil {
+			opts.Severities = make(map[string]lint.Severity)
+		}
+		opts.Severities[check] = sev
+	}
+	return nil
+}
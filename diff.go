@@ -0,0 +1,43 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+var diffRef = flag.String("diff", "", "only report issues in functions changed since git `ref`, or in the unified diff read from stdin with -")
+
+// readChanges finds the lines changed since diffRef, with git, or reads
+// them from a diff on stdin.
+func readChanges() (lint.Changes, error) {
+	if *diffRef == "-" {
+		return lint.ParseDiff(os.Stdin, ".")
+	}
+	root, err := git("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	diff, err := git("diff", "-U0", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", *diffRef, "--")
+	if err != nil {
+		return nil, err
+	}
+	return lint.ParseDiff(strings.NewReader(diff), strings.TrimSpace(root))
+}
+
+func git(args ...string) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %s: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
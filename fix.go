@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/agflow/splint/lint"
+)
+
+var fixMode = flag.Bool("fix", false, "rewrite the files to fix the empty-if issues, removing or inverting the ifs")
+var fixDryRun = flag.Bool("fix-dry-run", false, "print the diff -fix would apply, without writing the files")
+
+// fix fixes the empty ifs of the files in args, or prints the diff of the
+// fixes with -fix-dry-run, returning whether anything failed.
+func fix(w io.Writer, args []string, opts lint.Options) bool {
+	paths, _, err := lint.Expand(args, opts)
+	failed := err != nil
+	if err != nil {
+		fmt.Println(err)
+	}
+	for _, path := range paths {
+		if path == "-" {
+			continue
+		}
+		if err := fixFile(w, path, opts); err != nil {
+			fmt.Println(err)
+			failed = true
+		}
+	}
+	return failed
+}
+
+func fixFile(w io.Writer, path string, opts lint.Options) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	fixed, n, err := lint.FixEmptyIfs(path, src, opts)
+	if err != nil || n == 0 {
+		return err
+	}
+	if *fixDryRun {
+		return printDiff(w, path, fixed)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, fixed, info.Mode()); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s: fixed %d empty ifs\n", path, n)
+	return nil
+}
+
+// printDiff prints a unified diff between a file and its fixed source,
+// with diff(1).
+func printDiff(w io.Writer, path string, fixed []byte) error {
+	cmd := exec.Command("diff", "-u", "-L", path, "-L", path+" (fixed)", path, "-")
+	cmd.Stdin = bytes.NewReader(fixed)
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	var exit *exec.ExitError
+	// diff exits with status 1 when the files differ
+	if err := cmd.Run(); err != nil && !(errors.As(err, &exit) && exit.ExitCode() == 1) {
+		return fmt.Errorf("diff %s: %s", path, err)
+	}
+	return nil
+}
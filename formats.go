@@ -0,0 +1,206 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/agflow/splint/lint"
+)
+
+var githubData = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var githubProperty = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+var githubCommand = map[lint.Severity]string{
+	lint.SeverityError:   "error",
+	lint.SeverityWarning: "warning",
+	lint.SeverityInfo:    "notice",
+}
+
+// writeGitHub prints GitHub Actions workflow commands, which show up as
+// annotations on the offending lines of a pull request.
+func writeGitHub(w io.Writer, summary *lint.Summary) error {
+	for _, section := range summary.Sections() {
+		for _, o := range section.Offenders {
+			_, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,endLine=%d::%s\n", githubCommand[o.Severity],
+				githubProperty.Replace(o.Position.Filename), o.Position.Line, o.Position.Column, max(o.End.Line, o.Position.Line),
+				githubData.Replace(o.Message()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var codeQualitySeverity = map[lint.Severity]string{
+	lint.SeverityError:   "major",
+	lint.SeverityWarning: "minor",
+	lint.SeverityInfo:    "info",
+}
+
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+// codeQualityFingerprint identifies an issue independently of its line, so
+// GitLab can tell new issues from ones that merely moved.  n tells apart
+// repeated issues of the same check in the same function.
+func codeQualityFingerprint(check string, o *lint.Offender, n int) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%d", check, o.Position.Filename, o.Function, n)))
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeCodeQuality prints the issues as a GitLab Code Quality report.
+func writeCodeQuality(w io.Writer, summary *lint.Summary) error {
+	issues := []codeQualityIssue{}
+	for _, section := range summary.Sections() {
+		seen := make(map[string]int)
+		for _, o := range section.Offenders {
+			key := o.Position.Filename + "\x00" + o.Function
+			issues = append(issues, codeQualityIssue{
+				Description: o.Message(),
+				CheckName:   section.Check,
+				Fingerprint: codeQualityFingerprint(section.Check, o, seen[key]),
+				Severity:    codeQualitySeverity[o.Severity],
+				Location: codeQualityLocation{
+					Path:  o.Position.Filename,
+					Lines: codeQualityLines{Begin: o.Position.Line, End: max(o.End.Line, o.Position.Line)},
+				},
+			})
+			seen[key]++
+		}
+	}
+	data, err := json.MarshalIndent(issues, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json encode error: %s", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// csvWriter returns a writer printing one row per issue, separated by
+// comma, for spreadsheets and BI tools.  The threshold is left empty for
+// the checks that have none.
+func csvWriter(comma rune) func(io.Writer, *lint.Summary) error {
+	return func(w io.Writer, summary *lint.Summary) error {
+		cw := csv.NewWriter(w)
+		cw.Comma = comma
+		cw.Write([]string{"file", "line", "column", "function", "check", "count", "threshold"})
+		for _, section := range summary.Sections() {
+			for _, o := range section.Offenders {
+				threshold := ""
+				if o.Threshold != nil {
+					threshold = strconv.FormatFloat(*o.Threshold, 'g', -1, 64)
+				}
+				cw.Write([]string{o.Position.Filename, strconv.Itoa(o.Position.Line), strconv.Itoa(o.Position.Column),
+					o.Function, o.Check, strconv.Itoa(o.Count), threshold})
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+}
+
+// writeTAP prints a Test Anything Protocol report with a test point per
+// check, which fails when the check found errors.  Its issues are listed
+// in a YAML block, and checks that are turned off are skipped.
+func writeTAP(w io.Writer, summary *lint.Summary) error {
+	sections := summary.Sections()
+	fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(sections))
+	for i, section := range sections {
+		status := "ok"
+		if summary.HasErrors(section.Check) {
+			status = "not ok"
+		}
+		if turnedOff(section.Check) {
+			fmt.Fprintf(w, "%s %d - %s # SKIP turned off\n", status, i+1, section.Check)
+			continue
+		}
+		fmt.Fprintf(w, "%s %d - %s\n", status, i+1, section.Check)
+		if len(section.Offenders) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  ---\n  message: %s\n  issues:\n", strconv.Quote(section.Name))
+		for _, o := range section.Offenders {
+			fmt.Fprintf(w, "    - %s\n", strconv.Quote(fmt.Sprintf("%s: %s", o.Position, o.Message())))
+		}
+		fmt.Fprintln(w, "  ...")
+	}
+	return nil
+}
+
+var templateText = flag.String("template", "", "with -format=template, the text/template `text` printed for every issue, like '{{.Position}} {{.Check}} {{.Message}}'")
+
+// writeTemplate prints every issue with the template given in -template,
+// executed on the lint.Offender.  A newline is added if the template
+// doesn't end with one.
+func writeTemplate(w io.Writer, summary *lint.Summary) error {
+	text := *templateText
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("issue").Parse(text)
+	if err != nil {
+		return err
+	}
+	for _, section := range summary.Sections() {
+		for _, o := range section.Offenders {
+			if err := tmpl.Execute(w, o); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// printText prints an issue as text, indented, followed by the hint of its
+// suggestion, its snippet and its last change when known.
+func printText(w io.Writer, indent string, o *lint.Offender) {
+	fmt.Fprintf(w, "%s%s\n", indent, o)
+	if o.Suggestion != nil {
+		fmt.Fprintf(w, "%s\thint: %s\n", indent, o.Suggestion)
+	}
+	if o.ParamObject != nil {
+		fmt.Fprintf(w, "%s\thint: %s\n", indent, o.ParamObject)
+	}
+	for i, line := range o.Snippet {
+		if i > 0 && line.Line > o.Snippet[i-1].Line+1 {
+			fmt.Fprintf(w, "%s\t%6s\n", indent, "...")
+		}
+		fmt.Fprintf(w, "%s\t%6d  %s\n", indent, line.Line, line.Text)
+	}
+	if o.Blame != nil {
+		fmt.Fprintf(w, "%s\tlast changed by %s on %s in %.8s\n", indent, o.Blame.Author, o.Blame.Date.Format(time.DateOnly), o.Blame.Commit)
+	}
+}
+
+// streamers make the reporters of the output formats that print every
+// issue as soon as it is found, rather than waiting for the end of the
+// run.
+var streamers = map[string]func(io.Writer, lint.Options) lint.Reporter{
+	"text":   newTextReporter,
+	"ndjson": newNDJSONReporter,
+}
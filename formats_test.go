@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/agflow/splint/lint"
+)
+
+// formatSummary analyzes a file named so that its name needs escaping,
+// with a function over the statement threshold and an empty if.
+func formatSummary(t *testing.T) (*lint.Summary, string) {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "a,b:c.go")
+	src := "package a\n\nfunc f(n int) {\n\tn++\n\tn++\n\tn++\n}\n\nfunc g(b bool) {\n\tif b {\n\t}\n}\n"
+	if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	opts := lint.DefaultOptions()
+	opts.StatementThreshold = 3
+	summary, err := lint.Run([]string{filename}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return summary, filename
+}
+
+func TestWriteGitHub(t *testing.T) {
+	summary, filename := formatSummary(t)
+	var b strings.Builder
+	if err := writeGitHub(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	file := githubProperty.Replace(filepath.Dir(filename)) + "/a%2Cb%3Ac.go"
+	want := "::error file=" + file + ",line=3,col=1,endLine=7::function f too long: 4\n" +
+		"::error file=" + file + ",line=10,col=2,endLine=11::function g if with empty body\n" +
+		"::error file=" + file + ",line=9,col=8,endLine=9::function g bool function param\n"
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+	if got := githubData.Replace("100%,\r\n:"); got != "100%25,%0D%0A:" {
+		t.Errorf("message escaped as %s", got)
+	}
+}
+
+func TestWriteCodeQuality(t *testing.T) {
+	summary, filename := formatSummary(t)
+	var b strings.Builder
+	if err := writeCodeQuality(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	var issues []codeQualityIssue
+	if err := json.Unmarshal([]byte(b.String()), &issues); err != nil {
+		t.Fatal(err)
+	}
+	want := []codeQualityLocation{
+		{filename, codeQualityLines{3, 7}},
+		{filename, codeQualityLines{10, 11}},
+		{filename, codeQualityLines{9, 9}},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("%d issues, want %d", len(issues), len(want))
+	}
+	seen := make(map[string]bool)
+	for i, issue := range issues {
+		if issue.Location != want[i] || issue.Severity != "major" || seen[issue.Fingerprint] {
+			t.Errorf("issue %d: %+v, want major, at %+v, with a new fingerprint", i, issue, want[i])
+		}
+		seen[issue.Fingerprint] = true
+	}
+	b.Reset()
+	if err := writeCodeQuality(&b, &lint.Summary{}); err != nil || b.String() != "[]\n" {
+		t.Errorf("no issues written as %q, %v, want []", b.String(), err)
+	}
+}
+
+// Fingerprints don't depend on lines, and tell repeated issues apart.
+func TestCodeQualityFingerprint(t *testing.T) {
+	o := &lint.Offender{Function: "f", Position: token.Position{Filename: "a.go", Line: 3}}
+	moved := *o
+	moved.Position.Line += 10
+	if codeQualityFingerprint("c", o, 0) != codeQualityFingerprint("c", &moved, 0) {
+		t.Error("fingerprint changed with the line")
+	}
+	if codeQualityFingerprint("c", o, 0) == codeQualityFingerprint("c", o, 1) {
+		t.Error("same fingerprint for repeated issues")
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	summary, filename := formatSummary(t)
+	want := [][]string{
+		{"file", "line", "column", "function", "check", "count", "threshold"},
+		{filename, "3", "1", "f", lint.CheckStatementCount, "4", "3"},
+		{filename, "10", "2", "g", lint.CheckEmptyIf, "0", ""},
+		{filename, "9", "8", "g", lint.CheckBoolParam, "0", ""},
+	}
+	for _, comma := range []rune{',', '\t'} {
+		var b strings.Builder
+		if err := csvWriter(comma)(&b, summary); err != nil {
+			t.Fatal(err)
+		}
+		r := csv.NewReader(strings.NewReader(b.String()))
+		r.Comma = comma
+		got, err := r.ReadAll()
+		if err != nil || !slices.EqualFunc(got, want, slices.Equal) {
+			t.Errorf("separated by %q: read back %q, %v, want %q", comma, got, err, want)
+		}
+	}
+}
+
+func TestWriteTAP(t *testing.T) {
+	summary, filename := formatSummary(t)
+	var b strings.Builder
+	if err := writeTAP(&b, summary); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	n := len(summary.Sections())
+	if want := fmt.Sprintf("TAP version 13\n1..%d\n", n); !strings.HasPrefix(out, want) {
+		t.Errorf("output starts with %q, want %q", out[:min(len(out), 20)], want)
+	}
+	points := tapPoints(out)
+	if len(points) != n {
+		t.Errorf("%d test points, want %d", len(points), n)
+	}
+	for check, want := range map[string]string{
+		"statement-count":              "not ok 1 - statement-count",
+		"line-count # SKIP turned off": "ok 2 - line-count # SKIP turned off",
+		"init-length":                  "ok 3 - init-length",
+	} {
+		if points[check] != want {
+			t.Errorf("test point %q, want %q", points[check], want)
+		}
+	}
+	if !strings.HasPrefix(points["empty-if"], "not ok ") {
+		t.Errorf("test point %q, want not ok", points["empty-if"])
+	}
+	issues := "not ok 1 - statement-count\n  ---\n  message: \"Functions above statement threshold\"\n  issues:\n" +
+		"    - " + strconv.Quote(filename+":3:1: function f too long: 4") + "\n  ...\n"
+	if !strings.Contains(out, issues) {
+		t.Errorf("no %q in\n%s", issues, out)
+	}
+}
+
+// tapPoints returns the test point lines of a TAP report by their
+// description.
+func tapPoints(out string) map[string]string {
+	points := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "ok ") || strings.HasPrefix(line, "not ok ") {
+			_, check, _ := strings.Cut(line, " - ")
+			points[check] = line
+		}
+	}
+	return points
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/agflow/splint/lint"
+	_ "modernc.org/sqlite"
+)
+
+var historyFile = flag.String("history", "", "append the metrics of every function, with the commit and time of the run, to the SQLite database `file`")
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY,
+	commit_sha TEXT NOT NULL,
+	time TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS functions (
+	run INTEGER NOT NULL REFERENCES runs(id),
+	file TEXT NOT NULL,
+	function TEXT NOT NULL,
+	statements INTEGER,
+	lines INTEGER,
+	params INTEGER,
+	results INTEGER,
+	returns INTEGER,
+	locals INTEGER,
+	nesting INTEGER,
+	cognitive INTEGER,
+	cyclomatic INTEGER,
+	maintainability REAL
+);
+CREATE INDEX IF NOT EXISTS functions_name ON functions (function, file);
+`
+
+// openHistory opens the history database, creating its tables if needed.
+func openHistory(filename string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", filename)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return db, nil
+}
+
+// headCommit returns the commit checked out in the current directory, or
+// an empty string outside of a git repository.
+func headCommit() string {
+	out, err := git("rev-parse", "HEAD")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// recordHistory appends a run with the metrics of every function of a
+// summary to the history database, in one transaction.
+func recordHistory(filename string, summary *lint.Summary) error {
+	db, err := openHistory(filename)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	res, err := tx.Exec("INSERT INTO runs (commit_sha, time) VALUES (?, ?)", headCommit(), time.Now().UTC().Format(time.RFC3339))
+	if err != nil {
+		return err
+	}
+	run, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := insertFunctions(tx, run, summary.Functions); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertFunctions adds the metrics of functions to a run.
+func insertFunctions(tx *sql.Tx, run int64, functions []*lint.FunctionMetrics) error {
+	stmt, err := tx.Prepare(`INSERT INTO functions (run, file, function, statements, lines, params, results,
+		returns, locals, nesting, cognitive, cyclomatic, maintainability) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, m := range functions {
+		_, err := stmt.Exec(run, m.Filename, m.Function, m.Statements, m.Lines, m.Params, m.Results,
+			m.Returns, m.Locals, m.Nesting, m.Cognitive, m.Cyclomatic, m.Maintainability)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// historyRuns sums up every run of the history, oldest first.
+const historyRuns = `
+SELECT r.time, r.commit_sha, COUNT(f.function), COALESCE(SUM(f.statements), 0),
+	COALESCE(AVG(f.statements), 0), COALESCE(MAX(f.statements), 0),
+	COALESCE(AVG(f.cognitive), 0), COALESCE(MAX(f.cognitive), 0)
+FROM runs r LEFT JOIN functions f ON f.run = r.id
+GROUP BY r.id ORDER BY r.id`
+
+// historyFunction lists the metrics of the functions with a name in
+// every run, oldest first.
+const historyFunction = `
+SELECT r.time, r.commit_sha, f.file, f.statements, f.lines, f.params, f.cognitive, f.cyclomatic
+FROM runs r JOIN functions f ON f.run = r.id
+WHERE f.function = ?
+ORDER BY f.file, r.id`
+
+// history prints the trends recorded in a history database: the totals of
+// every run, or the metrics of one function across runs.  It returns
+// whether the database could not be read.
+func history(w io.Writer, filename string, function string) bool {
+	db, err := openHistory(filename)
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	defer db.Close()
+	if function == "" {
+		err = printRuns(w, db)
+	} else {
+		err = printFunctionHistory(w, db, function)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	return false
+}
+
+// shortCommit abbreviates a commit SHA, which is empty for runs outside
+// of a git repository.
+func shortCommit(sha string) string {
+	if sha == "" {
+		return "-"
+	}
+	return sha[:min(len(sha), 8)]
+}
+
+func printRuns(w io.Writer, db *sql.DB) error {
+	rows, err := db.Query(historyRuns)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var when, sha string
+		var functions, statements, maxStatements, maxCognitive int
+		var avgStatements, avgCognitive float64
+		err := rows.Scan(&when, &sha, &functions, &statements, &avgStatements, &maxStatements, &avgCognitive, &maxCognitive)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s %s: %d functions, %d statements (avg %.1f, max %d), cognitive avg %.1f, max %d\n",
+			when, shortCommit(sha), functions, statements, avgStatements, maxStatements, avgCognitive, maxCognitive)
+	}
+	return rows.Err()
+}
+
+func printFunctionHistory(w io.Writer, db *sql.DB, function string) error {
+	rows, err := db.Query(historyFunction, function)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	found := false
+	for rows.Next() {
+		var when, sha, file string
+		var statements, lines, params, cognitive, cyclomatic int
+		if err := rows.Scan(&when, &sha, &file, &statements, &lines, &params, &cognitive, &cyclomatic); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "%s %s %s: %d statements, %d lines, %d params, cognitive %d, cyclomatic %d\n",
+			when, shortCommit(sha), file, statements, lines, params, cognitive, cyclomatic)
+		found = true
+	}
+	if err := rows.Err(); err != nil || found {
+		return err
+	}
+	return fmt.Errorf("no history of function %s", function)
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/agflow/splint/lint"
+)
+
+type htmlFinding struct {
+	Check string
+	*lint.Offender
+}
+
+// Link points at the offending file and line, for browsers and editors
+// that understand file URLs.
+func (f htmlFinding) Link() template.URL {
+	name, err := filepath.Abs(f.Position.Filename)
+	if err != nil {
+		name = f.Position.Filename
+	}
+	return template.URL(fmt.Sprintf("file://%s#L%d", filepath.ToSlash(name), f.Position.Line))
+}
+
+type htmlFile struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+type htmlSection struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+type htmlReport struct {
+	Sections []htmlSection
+	Files    []htmlFile
+	Total    int
+}
+
+func newHTMLReport(summary *lint.Summary) *htmlReport {
+	report := new(htmlReport)
+	byFile := make(map[string][]htmlFinding)
+	for _, section := range summary.Sections() {
+		hs := htmlSection{Name: section.Name}
+		for _, o := range section.Offenders {
+			f := htmlFinding{section.Name, o}
+			hs.Findings = append(hs.Findings, f)
+			byFile[o.Position.Filename] = append(byFile[o.Position.Filename], f)
+			report.Total++
+		}
+		report.Sections = append(report.Sections, hs)
+	}
+	for name, findings := range byFile {
+		sort.SliceStable(findings, func(i, j int) bool {
+			return findings[i].Position.Offset < findings[j].Position.Offset
+		})
+		report.Files = append(report.Files, htmlFile{name, findings})
+	}
+	sort.Slice(report.Files, func(i, j int) bool {
+		return report.Files[i].Name < report.Files[j].Name
+	})
+	return report
+}
+
+func writeHTML(w io.Writer, summary *lint.Summary) error {
+	return htmlTemplate.Execute(w, newHTMLReport(summary))
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>splint report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.8em; text-align: left; }
+th { background: #eee; cursor: pointer; user-select: none; }
+td.num { text-align: right; }
+nav a { margin-right: 1em; }
+</style>
+</head>
+<body>
+<h1>splint report</h1>
+<p>{{.Total}} issues in {{len .Files}} files.</p>
+<nav>{{range $i, $s := .Sections}}<a href="#check-{{$i}}">{{$s.Name}} ({{len $s.Findings}})</a>{{end}}</nav>
+
+<h2>By check</h2>
+{{range $i, $s := .Sections}}
+<h3 id="check-{{$i}}">{{$s.Name}}</h3>
+{{if $s.Findings}}
+<table class="sortable">
+<thead><tr><th>Position</th><th>Severity</th><th>Function</th><th>Count</th></tr></thead>
+<tbody>
+{{range $s.Findings}}<tr><td><a href="{{.Link}}">{{.Position}}</a></td><td>{{.Severity}}</td><td>{{.Function}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</tbody>
+</table>
+{{else}}<p>None.</p>{{end}}
+{{end}}
+
+<h2>By file</h2>
+{{range .Files}}
+<h3>{{.Name}}</h3>
+<table class="sortable">
+<thead><tr><th>Line</th><th>Check</th><th>Severity</th><th>Function</th><th>Count</th></tr></thead>
+<tbody>
+{{range .Findings}}<tr><td class="num"><a href="{{.Link}}">{{.Position.Line}}</a></td><td>{{.Check}}</td><td>{{.Severity}}</td><td>{{.Function}}</td><td class="num">{{.Count}}</td></tr>
+{{end}}</tbody>
+</table>
+{{end}}
+
+<script>
+document.querySelectorAll("table.sortable th").forEach(function(th) {
+	th.addEventListener("click", function() {
+		var table = th.closest("table");
+		var body = table.tBodies[0];
+		var col = Array.prototype.indexOf.call(th.parentNode.children, th);
+		var asc = th.dataset.order !== "asc";
+		th.dataset.order = asc ? "asc" : "desc";
+		var rows = Array.prototype.slice.call(body.rows);
+		rows.sort(function(a, b) {
+			var x = a.cells[col].textContent, y = b.cells[col].textContent;
+			var n = parseFloat(x) - parseFloat(y);
+			var c = isNaN(n) ? x.localeCompare(y, undefined, {numeric: true}) : n;
+			return asc ? c : -c;
+		});
+		rows.forEach(function(r) { body.appendChild(r); });
+	});
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,399 @@
+package lint
+
+import "fmt"
+
+func (s *Summary) addSuppressed(o *Offender, reason string) {
+	s.Suppressed = append(s.Suppressed, &Suppression{o, reason})
+	s.NumSuppressed++
+}
+
+func (s *Summary) addOverride(ov *Override) {
+	s.Overrides = append(s.Overrides, ov)
+	s.NumOverrides++
+}
+
+// adders holds the method adding the offenders of each built-in check to
+// a summary.
+var adders = map[string]func(*Summary, *Offender){
+	CheckStatementCount:      (*Summary).addStatement,
+	CheckLineCount:           (*Summary).addLines,
+	CheckInitLength:          (*Summary).addInit,
+	CheckParamCount:          (*Summary).addParam,
+	CheckVariadic:            (*Summary).addVariadic,
+	CheckContext:             (*Summary).addContext,
+	CheckResultCount:         (*Summary).addResult,
+	CheckReturnCount:         (*Summary).addReturn,
+	CheckLocalCount:          (*Summary).addLocals,
+	CheckIfChain:             (*Summary).addIfChain,
+	CheckEmptyIf:             (*Summary).addEmptyIfBody,
+	CheckEmptyElse:           (*Summary).addEmptyElse,
+	CheckEmptyFor:            (*Summary).addEmptyFor,
+	CheckEmptySwitch:         (*Summary).addEmptySwitch,
+	CheckEmptySelect:         (*Summary).addEmptySelect,
+	CheckCondition:           (*Summary).addCondition,
+	CheckGuardClause:         (*Summary).addGuardClause,
+	CheckNamedResults:        (*Summary).addNamedResult,
+	CheckMethodChain:         (*Summary).addMethodChain,
+	CheckGoroutines:          (*Summary).addGoroutines,
+	CheckGoInLoop:            (*Summary).addGoInLoop,
+	CheckPanics:              (*Summary).addPanics,
+	CheckExit:                (*Summary).addExit,
+	CheckFanOut:              (*Summary).addFanOut,
+	CheckFanIn:               (*Summary).addFanIn,
+	CheckUnreachable:         (*Summary).addUnreachable,
+	CheckSwitchDefault:       (*Summary).addSwitchDefault,
+	CheckTypeSwitchCases:     (*Summary).addTypeSwitch,
+	CheckClosureDepth:        (*Summary).addClosureDepth,
+	CheckLongIf:              (*Summary).addLongIfBody,
+	CheckSwitchCases:         (*Summary).addSwitch,
+	CheckLongCase:            (*Summary).addLongCase,
+	CheckLabels:              (*Summary).addLabels,
+	CheckDiscardedError:      (*Summary).addDiscardedErrors,
+	CheckMagicNumber:         (*Summary).addMagic,
+	CheckBoolParam:           (*Summary).addBoolParam,
+	CheckCognitiveComplexity: (*Summary).addCognitive,
+	CheckDuplicate:           (*Summary).addDuplicate,
+	CheckCommentDensity:      (*Summary).addComments,
+	CheckHalstead:            (*Summary).addHalstead,
+	CheckMaintainability:     (*Summary).addMaintainability,
+	CheckFileLength:          (*Summary).addLongFile,
+	CheckFileFunctions:       (*Summary).addFileFunctions,
+	CheckImports:             (*Summary).addImports,
+	CheckGlobals:             (*Summary).addGlobals,
+	CheckStructFields:        (*Summary).addStruct,
+	CheckInterfaceMethods:    (*Summary).addInterface,
+	CheckTypeMethods:         (*Summary).addTypeMethods,
+	CheckMixedReceivers:      (*Summary).addMixedReceivers,
+	CheckLineLength:          (*Summary).addLongLine,
+	CheckMarkers:             (*Summary).addMarkerFile,
+}
+
+// adder returns the method adding an offender of a check to the summary.
+func (s *Summary) adder(check string) func(*Offender) {
+	if add, ok := adders[check]; ok {
+		return func(o *Offender) { add(s, o) }
+	}
+	if isCustom(check) {
+		return s.addCustom
+	}
+	panic("lint: unknown check " + check)
+}
+
+func (s *Summary) addCustom(o *Offender) {
+	if s.Custom == nil {
+		s.Custom = make(map[string][]*Offender)
+	}
+	s.Custom[o.Check] = append(s.Custom[o.Check], o)
+	s.record(o)
+}
+
+func (s *Summary) addStatement(o *Offender) {
+	s.Statement = append(s.Statement, o)
+	s.NumAboveStatementThreshold++
+	o.warning("too long")
+	s.record(o)
+}
+
+func (s *Summary) addLines(o *Offender) {
+	s.Lines = append(s.Lines, o)
+	s.NumAboveLineThreshold++
+	o.warning("too many lines")
+	s.record(o)
+}
+
+func (s *Summary) addInit(o *Offender) {
+	s.Inits = append(s.Inits, o)
+	s.NumAboveInitThreshold++
+	o.warning("too long for an init")
+	s.record(o)
+}
+
+func (s *Summary) addParam(o *Offender) {
+	s.Param = append(s.Param, o)
+	s.NumAboveParamThreshold++
+	o.warning("too many params")
+	s.record(o)
+}
+
+// addVariadic adds a function with a variadic param, either with too many
+// other params, or, without a count, taking interface{}.
+func (s *Summary) addVariadic(o *Offender) {
+	s.Variadics = append(s.Variadics, o)
+	s.NumVariadics++
+	if o.Count > 0 {
+		o.warning("variadic with too many other params")
+	} else {
+		o.warnNoCount("variadic interface{} param")
+	}
+	s.record(o)
+}
+
+// addContext adds a function taking a context.Context as the param at the
+// position counted, or, without a count, inside a struct param.
+func (s *Summary) addContext(o *Offender) {
+	s.Contexts = append(s.Contexts, o)
+	s.NumMisplacedContexts++
+	if o.Count > 0 {
+		o.warning("context.Context not the first param")
+	} else {
+		o.warnNoCount("context.Context inside a struct param")
+	}
+	s.record(o)
+}
+
+func (s *Summary) addBoolParam(o *Offender) {
+	s.BoolParams = append(s.BoolParams, o)
+	s.NumWithBoolParams++
+	o.warnNoCount("bool function param")
+	s.record(o)
+}
+
+func (s *Summary) addResult(o *Offender) {
+	s.Result = append(s.Result, o)
+	s.NumAboveResultThreshold++
+	o.warning("too many results")
+	s.record(o)
+}
+
+func (s *Summary) addReturn(o *Offender) {
+	s.Returns = append(s.Returns, o)
+	s.NumAboveReturnThreshold++
+	o.warning("too many returns")
+	s.record(o)
+}
+
+func (s *Summary) addLocals(o *Offender) {
+	s.Locals = append(s.Locals, o)
+	s.NumAboveLocalThreshold++
+	o.warning("too many local variables")
+	s.record(o)
+}
+
+func (s *Summary) addEmptyIfBody(o *Offender) {
+	s.EmptyIfs = append(s.EmptyIfs, o)
+	s.NumEmptyIfs++
+	o.warnNoCount("if with empty body")
+	s.record(o)
+}
+
+func (s *Summary) addEmptyElse(o *Offender) {
+	s.EmptyElses = append(s.EmptyElses, o)
+	s.NumEmptyElses++
+	o.warnNoCount("empty else branch")
+	s.record(o)
+}
+
+func (s *Summary) addEmptyFor(o *Offender) {
+	s.EmptyFors = append(s.EmptyFors, o)
+	s.NumEmptyFors++
+	o.warnNoCount("for with empty body")
+	s.record(o)
+}
+
+func (s *Summary) addEmptySwitch(o *Offender) {
+	s.EmptySwitches = append(s.EmptySwitches, o)
+	s.NumEmptySwitches++
+	o.warnNoCount("switch without cases")
+	s.record(o)
+}
+
+func (s *Summary) addEmptySelect(o *Offender) {
+	s.EmptySelects = append(s.EmptySelects, o)
+	s.NumEmptySelects++
+	o.warnNoCount("select without cases")
+	s.record(o)
+}
+
+func (s *Summary) addCondition(o *Offender) {
+	s.Conditions = append(s.Conditions, o)
+	s.NumComplexConditions++
+	o.warning("condition with too many " + o.Metric)
+	s.record(o)
+}
+
+func (s *Summary) addGuardClause(o *Offender) {
+	s.GuardClauses = append(s.GuardClauses, o)
+	s.NumWrappedInIf++
+	o.message = fmt.Sprintf("function %s has %d%% of its statements in an if, consider returning early instead", o.Function, o.Count)
+	s.record(o)
+}
+
+func (s *Summary) addMethodChain(o *Offender) {
+	s.MethodChains = append(s.MethodChains, o)
+	s.NumLongChains++
+	o.warning("method chain too long")
+	s.record(o)
+}
+
+func (s *Summary) addGoroutines(o *Offender) {
+	s.Goroutines = append(s.Goroutines, o)
+	s.NumAboveGoroutineThreshold++
+	o.warning("starts too many goroutines")
+	s.record(o)
+}
+
+func (s *Summary) addGoInLoop(o *Offender) {
+	s.GoInLoops = append(s.GoInLoops, o)
+	s.NumGoInLoops++
+	o.warnNoCount("starts a goroutine in a loop")
+	s.record(o)
+}
+
+func (s *Summary) addPanics(o *Offender) {
+	s.Panics = append(s.Panics, o)
+	s.NumWithPanics++
+	o.warning("too many panics and recovers")
+	s.record(o)
+}
+
+func (s *Summary) addExit(o *Offender) {
+	s.Exits = append(s.Exits, o)
+	s.NumExits++
+	o.warnNoCount("ends the program outside of main")
+	s.record(o)
+}
+
+func (s *Summary) addFanOut(o *Offender) {
+	s.FanOuts = append(s.FanOuts, o)
+	s.NumAboveFanOutThreshold++
+	o.warning("calls too many functions")
+	s.record(o)
+}
+
+func (s *Summary) addFanIn(o *Offender) {
+	s.FanIns = append(s.FanIns, o)
+	s.NumAboveFanInThreshold++
+	o.warning("too complex for the number of places calling it")
+	s.record(o)
+}
+
+func (s *Summary) addUnreachable(o *Offender) {
+	s.Unreachable = append(s.Unreachable, o)
+	s.NumUnreachable++
+	o.warnNoCount("unreachable statement")
+	s.record(o)
+}
+
+func (s *Summary) addSwitchDefault(o *Offender) {
+	s.NoDefaults = append(s.NoDefaults, o)
+	s.NumWithoutDefault++
+	o.warning("switch without default")
+	s.record(o)
+}
+
+func (s *Summary) addClosureDepth(o *Offender) {
+	s.DeepClosures = append(s.DeepClosures, o)
+	s.NumDeepClosures++
+	o.warning("nested too deep in function literals")
+	s.record(o)
+}
+
+func (s *Summary) addNamedResult(o *Offender) {
+	s.NamedResults = append(s.NamedResults, o)
+	s.NumNamedResultIssues++
+	if o.Metric == "shadowed" {
+		o.warnNoCount("shadows a named result")
+	} else {
+		o.warnNoCount("named result only returned by naked returns")
+	}
+	s.record(o)
+}
+
+func (s *Summary) addLongIfBody(o *Offender) {
+	s.LongIfs = append(s.LongIfs, o)
+	s.NumLongIfs++
+	o.warnNoCount("if with long body")
+	s.record(o)
+}
+
+func (s *Summary) addSwitch(o *Offender) {
+	s.Switches = append(s.Switches, o)
+	s.NumLongSwitches++
+	o.warning("switch with too many cases")
+	s.record(o)
+}
+
+func (s *Summary) addTypeSwitch(o *Offender) {
+	s.TypeSwitches = append(s.TypeSwitches, o)
+	s.NumAboveTypeSwitchCaseThreshold++
+	o.warning("type switch with too many cases")
+	s.record(o)
+}
+
+func (s *Summary) addLongCase(o *Offender) {
+	s.LongCases = append(s.LongCases, o)
+	s.NumLongCases++
+	o.warning("case with long body")
+	s.record(o)
+}
+
+func (s *Summary) addLabels(o *Offender) {
+	s.Labels = append(s.Labels, o)
+	s.NumAboveLabelThreshold++
+	o.warning("too many gotos and labels")
+	s.record(o)
+}
+
+func (s *Summary) addDiscardedErrors(o *Offender) {
+	s.DiscardedErrs = append(s.DiscardedErrs, o)
+	s.NumDiscardingErrors++
+	o.warning("too many discarded errors")
+	s.record(o)
+}
+
+func (s *Summary) addMagic(o *Offender) {
+	s.MagicNumbers = append(s.MagicNumbers, o)
+	s.NumWithMagicNumbers++
+	o.warning("too many magic numbers")
+	s.record(o)
+}
+
+func (s *Summary) addIfChain(o *Offender) {
+	s.IfChains = append(s.IfChains, o)
+	s.NumIfChains++
+	o.warning("long if/else chain")
+	s.record(o)
+}
+
+func (s *Summary) addCognitive(o *Offender) {
+	s.Cognitive = append(s.Cognitive, o)
+	s.NumAboveCognitiveThreshold++
+	o.warning("too complex (cognitive complexity)")
+	s.record(o)
+}
+
+func (s *Summary) addDuplicate(o *Offender) {
+	s.Duplicates = append(s.Duplicates, o)
+	s.NumDuplicates++
+	msg := "duplicates " + o.Duplicates[0]
+	if n := len(o.Duplicates) - 1; n > 0 {
+		msg += fmt.Sprintf(" and %d more", n)
+	}
+	o.warning(msg)
+	s.record(o)
+}
+
+func (s *Summary) addComments(o *Offender) {
+	s.Uncommented = append(s.Uncommented, o)
+	s.NumUncommented++
+	o.warning("has too few comment lines for its length")
+	s.record(o)
+}
+
+func (s *Summary) addHalstead(o *Offender) {
+	s.Halstead = append(s.Halstead, o)
+	s.NumAboveHalsteadThreshold++
+	o.warning("Halstead " + o.Metric + " too high")
+	s.record(o)
+}
+
+func (s *Summary) addMaintainability(o *Offender) {
+	s.Unmaintainable = append(s.Unmaintainable, o)
+	s.NumBelowMaintainabilityThreshold++
+	if o.Function == "" {
+		o.fileWarning("maintainability index too low")
+	} else {
+		o.warning("maintainability index too low")
+	}
+	s.record(o)
+}
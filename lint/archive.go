@@ -0,0 +1,181 @@
+package lint
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IsArchive reports whether a Run argument is a zip, tar or gzipped tar
+// archive, whose go files are analyzed without extracting them.  Module
+// zips, as served by a GOPROXY, are zip archives.
+func IsArchive(filename string) bool {
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(filename, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// archiveFiles holds the contents of the go files of archives, by the
+// names they are reported under: the name of the archive joined with
+// their path inside it, like "mod.zip/example.com/mod@v1.0.0/mod.go".
+type archiveFiles map[string][]byte
+
+// maxArchiveFile and maxArchiveSize cap the size of a go file in an
+// archive, and of all its go files, which are read in memory.
+const (
+	maxArchiveFile = 16 << 20
+	maxArchiveSize = 256 << 20
+)
+
+// add reads the go files of an archive, returning their names in order.
+// Like walkTree, it skips the directories named in skip, which are counted
+// in skipped by name.
+func (files archiveFiles) add(archive string, skip []string, skipped map[string]int) ([]string, error) {
+	a := &archiveReader{name: archive, files: files, skip: skip, skipped: make(map[string]bool)}
+	var err error
+	if strings.HasSuffix(archive, ".zip") {
+		err = a.readZip()
+	} else {
+		err = a.readTar()
+	}
+	if err != nil {
+		return nil, err
+	}
+	for dir := range a.skipped {
+		skipped[path.Base(dir)]++
+	}
+	prefix := filepath.Clean(archive) + string(filepath.Separator)
+	var names []string
+	for name := range files {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// archiveReader reads the go files of an archive into files.
+type archiveReader struct {
+	name    string
+	files   archiveFiles
+	skip    []string
+	skipped map[string]bool // the skipped directories, by path in the archive
+	size    int             // the bytes of go files read
+}
+
+func (a *archiveReader) readZip() error {
+	r, err := zip.OpenReader(a.name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	for _, f := range r.File {
+		if a.skips(path.Dir(f.Name)) || f.FileInfo().IsDir() || !isGoFile(f.Name) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = a.read(f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *archiveReader) readTar() error {
+	file, err := os.Open(a.name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if !strings.HasSuffix(a.name, ".tar") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return a.readTarEntries(tar.NewReader(r))
+}
+
+func (a *archiveReader) readTarEntries(tr *tar.Reader) error {
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := h.Name
+		if h.Typeflag == tar.TypeDir {
+			name += "/"
+		}
+		if a.skips(path.Dir(name)) || h.Typeflag != tar.TypeReg || !isGoFile(name) {
+			continue
+		}
+		if err := a.read(name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// skips reports whether a directory of the archive is named in a.skip, or
+// is inside one, and records the outermost such directory.  It is given
+// the path.Dir of the names of entries, which is the directory itself for
+// directories, whose names end in a slash.
+func (a *archiveReader) skips(dir string) bool {
+	parts := strings.Split(strings.Trim(path.Clean("/"+dir), "/"), "/")
+	for i, part := range parts {
+		if contains(a.skip, part) {
+			a.skipped[path.Join(parts[:i+1]...)] = true
+			return true
+		}
+	}
+	return false
+}
+
+// read reads a go file of the archive, failing if it is larger than
+// maxArchiveFile, or makes the go files larger than maxArchiveSize.
+func (a *archiveReader) read(name string, r io.Reader) error {
+	src, err := io.ReadAll(io.LimitReader(r, maxArchiveFile+1))
+	if err != nil {
+		return err
+	}
+	if len(src) > maxArchiveFile {
+		return fmt.Errorf("%s is larger than %d bytes", name, maxArchiveFile)
+	}
+	if a.size += len(src); a.size > maxArchiveSize {
+		return fmt.Errorf("go files larger than %d bytes in all", maxArchiveSize)
+	}
+	a.files[filepath.Join(a.name, filepath.FromSlash(path.Clean("/"+name)))] = src
+	return nil
+}
+
+// readFile returns a function reading the files of the archives, and
+// other files with opts.ReadFile.
+func (files archiveFiles) readFile(opts Options) func(string) ([]byte, error) {
+	return func(filename string) ([]byte, error) {
+		if src, ok := files[filename]; ok {
+			return src, nil
+		}
+		return opts.readFile(filename)
+	}
+}
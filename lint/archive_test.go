@@ -0,0 +1,109 @@
+package lint
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// archiveEntries are the files of the archives of the tests, by path.
+var archiveEntries = map[string]string{
+	"mod@v1/a.go":              "package a\n\nfunc f() {}\n",
+	"mod@v1/README":            "not go",
+	"mod@v1/sub/b.go":          "package sub\n",
+	"mod@v1/vendor/x/c.go":     "package x\n",
+	"mod@v1/vendor/y/d.go":     "package y\n",
+	"mod@v1/sub/testdata/e.go": "package e\n",
+}
+
+func writeZip(t *testing.T, filename string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zw := zip.NewWriter(f)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, entries[name])
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGz(t *testing.T, filename string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, name := range slices.Sorted(maps.Keys(entries)) {
+		h := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(entries[name])), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(tw, entries[name])
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestArchives(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"mod.zip", "mod.tar.gz"} {
+		archive := filepath.Join(dir, name)
+		if strings.HasSuffix(name, ".zip") {
+			writeZip(t, archive, archiveEntries)
+		} else {
+			writeTarGz(t, archive, archiveEntries)
+		}
+		opts := DefaultOptions()
+		s, err := Run([]string{archive}, opts)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if s.Skipped["vendor"] != 1 || s.Skipped["testdata"] != 1 {
+			t.Errorf("%s: skipped %v, want one vendor and one testdata directory", name, s.Skipped)
+		}
+		found, _, err := Expand([]string{archive}, opts)
+		want := []string{filepath.Join(archive, "mod@v1", "a.go"), filepath.Join(archive, "mod@v1", "sub", "b.go")}
+		if err != nil || !slices.Equal(found, want) {
+			t.Errorf("%s: expanded to %v, %v, want %v", name, found, err, want)
+		}
+		opts.SkipDirs = nil
+		if found, _, _ := Expand([]string{archive}, opts); len(found) != 5 {
+			t.Errorf("%s: %d files without skipping directories, want 5", name, len(found))
+		}
+	}
+}
+
+func TestArchiveFileTooLarge(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "big.zip")
+	writeZip(t, archive, map[string]string{
+		"a.go":   "package a\n",
+		"big.go": "package big\n\n//" + strings.Repeat("x", maxArchiveFile),
+	})
+	if _, err := Run([]string{archive}, DefaultOptions()); err == nil || !strings.Contains(err.Error(), "big.go is larger than") {
+		t.Errorf("got error %v, want big.go too large", err)
+	}
+}
@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BaselineIssue identifies an issue recorded in a baseline.  Positions
+// are left out on purpose, so that issues survive unrelated edits to the
+// file they are in.
+type BaselineIssue struct {
+	Check    string
+	Filename string
+	Function string
+	Type     string `json:",omitempty"`
+}
+
+// Baseline is a list of known issues that should not be reported again,
+// letting existing code adopt splint without fixing everything first.
+type Baseline struct {
+	Issues []BaselineIssue
+
+	remaining map[BaselineIssue]int
+}
+
+func baselineIssue(o *Offender) BaselineIssue {
+	return BaselineIssue{
+		Check:    o.Check,
+		Filename: filepath.ToSlash(filepath.Clean(o.Filename)),
+		Function: o.Function,
+		Type:     o.Type,
+	}
+}
+
+// NewBaseline creates a baseline holding every issue reported in a
+// summary.
+func NewBaseline(s *Summary) *Baseline {
+	b := new(Baseline)
+	for _, section := range s.Sections() {
+		for _, o := range section.Offenders {
+			b.Issues = append(b.Issues, baselineIssue(o))
+		}
+	}
+	return b
+}
+
+// ReadBaseline reads a baseline written by Baseline.Write.
+func ReadBaseline(filename string) (*Baseline, error) {
+	b := new(Baseline)
+	if err := readJSON(filename, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// readJSON decodes the json in a file into v.
+func readJSON(filename string, v interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Write saves the baseline to a file as json.
+func (b *Baseline) Write(filename string) error {
+	data, err := json.MarshalIndent(b, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, append(data, '\n'), 0644)
+}
+
+// consume reports whether o is a known issue.  Each recorded issue
+// matches a single offender, so a function that gains a second issue of
+// the same kind still gets reported.  Baselines written before methods
+// were qualified by their receiver type, like Start for Server.Start,
+// still match them.
+func (b *Baseline) consume(o *Offender) bool {
+	if b.remaining == nil {
+		b.remaining = make(map[BaselineIssue]int)
+		for _, issue := range b.Issues {
+			b.remaining[issue]++
+		}
+	}
+	key := baselineIssue(o)
+	if _, name, ok := strings.Cut(key.Function, "."); ok && b.remaining[key] == 0 {
+		key.Function = name
+	}
+	if b.remaining[key] == 0 {
+		return false
+	}
+	b.remaining[key]--
+	return true
+}
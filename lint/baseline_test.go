@@ -0,0 +1,68 @@
+package lint
+
+import "testing"
+
+func TestBaselineConsume(t *testing.T) {
+	issue := func(function string) BaselineIssue {
+		return BaselineIssue{Check: CheckStatementCount, Filename: "a/a.go", Function: function}
+	}
+	offender := func(filename, function string) *Offender {
+		return &Offender{Check: CheckStatementCount, Filename: filename, Function: function}
+	}
+	tests := []struct {
+		name      string
+		issues    []BaselineIssue
+		offenders []*Offender
+		want      []bool
+	}{
+		{"recorded", []BaselineIssue{issue("f")}, []*Offender{offender("a/a.go", "f")}, []bool{true}},
+		{"unclean path", []BaselineIssue{issue("f")}, []*Offender{offender("a/./a.go", "f")}, []bool{true}},
+		{"other function", []BaselineIssue{issue("f")}, []*Offender{offender("a/a.go", "g")}, []bool{false}},
+		{"other file", []BaselineIssue{issue("f")}, []*Offender{offender("b/a.go", "f")}, []bool{false}},
+		{"once each", []BaselineIssue{issue("f")}, []*Offender{offender("a/a.go", "f"), offender("a/a.go", "f")}, []bool{true, false}},
+		{"recorded twice", []BaselineIssue{issue("f"), issue("f")}, []*Offender{offender("a/a.go", "f"), offender("a/a.go", "f")}, []bool{true, true}},
+		{"method", []BaselineIssue{issue("Server.Start")}, []*Offender{offender("a/a.go", "Server.Start")}, []bool{true}},
+		{"unqualified method", []BaselineIssue{issue("Start")}, []*Offender{offender("a/a.go", "Server.Start"), offender("a/a.go", "Server.Start")}, []bool{true, false}},
+		{"other receiver", []BaselineIssue{issue("Server.Start")}, []*Offender{offender("a/a.go", "Client.Start")}, []bool{false}},
+	}
+	for _, tt := range tests {
+		b := &Baseline{Issues: tt.issues}
+		for i, o := range tt.offenders {
+			if got := b.consume(o); got != tt.want[i] {
+				t.Errorf("%s: offender %d consumed is %v, want %v", tt.name, i, got, tt.want[i])
+			}
+		}
+	}
+}
+
+// TestBaselinePercentiles checks that measuring the functions for the
+// thresholds given as percentiles leaves the baseline to the real run.
+func TestBaselinePercentiles(t *testing.T) {
+	filename := writeSource(t, "package a\n\nfunc f() {\n\ta := 1\n\t_ = a\n}\n\nfunc g() {\n\ta := 1\n\t_ = a\n}\n")
+	opts := DefaultOptions()
+	opts.StatementThreshold = 1
+	recorded := NewBaseline(run(t, filename, opts))
+
+	tests := []struct {
+		name      string
+		measures  int
+		baselined int
+	}{
+		{"no measuring pass", 0, 2},
+		{"measuring pass", 1, 2},
+		{"measuring passes", 3, 2},
+	}
+	for _, tt := range tests {
+		opts.Baseline = &Baseline{Issues: recorded.Issues}
+		for range tt.measures {
+			if measured := run(t, filename, opts.Measuring()); measured.NumBaselined != 0 {
+				t.Errorf("%s: measuring pass baselined %d issues", tt.name, measured.NumBaselined)
+			}
+		}
+		summary := run(t, filename, opts)
+		if summary.NumBaselined != tt.baselined || countIssues(summary) != 0 {
+			t.Errorf("%s: %d issues in baseline, %d reported, want %d and none",
+				tt.name, summary.NumBaselined, countIssues(summary), tt.baselined)
+		}
+	}
+}
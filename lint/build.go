@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"bytes"
+	"go/build"
+	"io"
+	"path/filepath"
+)
+
+// platform reports whether opts target a platform or build tags, rather
+// than every variant of the files.
+func (opts *Options) platform() bool {
+	return opts.GOOS != "" || opts.GOARCH != "" || len(opts.BuildTags) > 0
+}
+
+// builds reports whether a file with the given source is part of the build
+// for the platform and tags of opts, going by its name, like foo_windows.go,
+// and its //go:build constraints, as the go tool would.  Files are always
+// built when opts target no platform, and when their constraints can't be
+// read, leaving the error to the parser.
+func (opts *Options) builds(filename string, src []byte) bool {
+	if !opts.platform() {
+		return true
+	}
+	ctx := build.Default
+	if opts.GOOS != "" {
+		ctx.GOOS = opts.GOOS
+	}
+	if opts.GOARCH != "" {
+		ctx.GOARCH = opts.GOARCH
+	}
+	ctx.BuildTags = opts.BuildTags
+	ctx.OpenFile = func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(src)), nil
+	}
+	name := filepath.Base(filename)
+	if !isGoFile(name) {
+		name += ".go" // like <stdin>
+	}
+	ok, err := ctx.MatchFile(".", name)
+	return ok || err != nil
+}
@@ -0,0 +1,36 @@
+package lint
+
+import "testing"
+
+func TestBuilds(t *testing.T) {
+	tests := []struct {
+		filename, constraint string
+		goos, goarch         string
+		tags                 []string
+		want                 bool
+	}{
+		{"a_windows.go", "//go:build ignore", "", "", nil, true},
+		{"a_windows.go", "", "linux", "", nil, false},
+		{"a_linux.go", "", "linux", "", nil, true},
+		{"a_linux_arm64.go", "", "linux", "amd64", nil, false},
+		{"a.go", "", "linux", "", nil, true},
+		{"a.go", "//go:build windows", "linux", "", nil, false},
+		{"a.go", "//go:build linux && amd64", "linux", "arm64", nil, false},
+		{"a.go", "//go:build linux && amd64", "linux", "amd64", nil, true},
+		{"a.go", "//go:build integration", "linux", "", nil, false},
+		{"a.go", "//go:build integration", "linux", "", []string{"integration"}, true},
+		{"a.go", "//go:build !integration", "linux", "", []string{"integration"}, false},
+		{"<stdin>", "//go:build windows", "linux", "", nil, false},
+		{"<stdin>", "", "linux", "", nil, true},
+		{"a.go", "//go:build (", "linux", "", nil, true},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.GOOS, opts.GOARCH, opts.BuildTags = tt.goos, tt.goarch, tt.tags
+		src := tt.constraint + "\n\npackage a\n"
+		if got := opts.builds(tt.filename, []byte(src)); got != tt.want {
+			t.Errorf("builds(%s, %q) for %s/%s %v = %v, want %v",
+				tt.filename, tt.constraint, tt.goos, tt.goarch, tt.tags, got, tt.want)
+		}
+	}
+}
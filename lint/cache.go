@@ -0,0 +1,204 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+// cacheVersion is part of every cache key.  Bump it whenever a check
+// changes what it finds, so older results are not reused.
+const cacheVersion = "68"
+
+// Cache stores what was found in each file on disk, keyed by the contents
+// of the file and the options used, so that unchanged files need not be
+// parsed again on the next run.
+type Cache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// OpenCache uses dir to store results, creating it if needed.  An empty
+// dir means a splint directory under the user's cache directory.
+func OpenCache(dir string) (*Cache, error) {
+	if dir == "" {
+		base, err := os.UserCacheDir()
+		if err != nil {
+			return nil, err
+		}
+		dir = filepath.Join(base, "splint")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// Dir returns the directory holding the cache.
+func (c *Cache) Dir() string {
+	return c.dir
+}
+
+// Stats returns how many files were found in the cache, and how many had
+// to be analyzed.
+func (c *Cache) Stats() (hits, misses int) {
+	return int(c.hits.Load()), int(c.misses.Load())
+}
+
+// cacheEntry is what is kept of a fileResult.  Offenders are stored with
+// their message, which is otherwise not encoded.
+type cacheEntry struct {
+	Found          []cachedOffender
+	Suppressed     []*Suppression
+	Overrides      []*Override
+	Packages       map[string]*PackageSummary
+	Functions      []*FunctionMetrics
+	Types          map[string]*typeMethods
+	Shapes         map[string][]*funcShape
+	CallSites      map[string]int
+	ComplexFuncs   map[string]*complexFunc
+	Markers        map[string]int
+	NumGenerated   int
+	NumConstrained int
+	NumCgo         int
+}
+
+type cachedOffender struct {
+	*Offender
+	Message string
+}
+
+// analyze is analyzeFile for files that may be in the cache.  Results are
+// only stored for files that parsed, and failing to store them is not an
+// error.
+func (c *Cache) analyze(filename string, opts Options) *fileResult {
+	r := new(fileResult)
+	src, err := opts.readFile(filename)
+	if err != nil {
+		r.err = err
+		return r
+	}
+	path := c.path(filename, src, opts)
+	if c.load(path, r) {
+		c.hits.Add(1)
+		return r
+	}
+	c.misses.Add(1)
+	r.summary = &Summary{Warn: func(o *Offender) { r.found = append(r.found, o) }}
+	if r.err = NewParser(filename, r.summary, opts).ParseSource(src); r.err == nil {
+		c.store(path, r)
+	}
+	return r
+}
+
+// path returns the file holding the results for a file with the given
+// name and contents.  The name is part of the key as it is reported in
+// positions and decides the package of the file.
+func (c *Cache) path(filename string, src []byte, opts Options) string {
+	abs, _ := filepath.Abs(filename)
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00", cacheVersion, cacheOptions(opts), filename, abs)
+	h.Write(src)
+	key := hex.EncodeToString(h.Sum(nil))
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// cacheOptions describes the options that change what is found in a file,
+// leaving out the ones only used when merging the results.
+func cacheOptions(opts Options) string {
+	opts.Exclude = nil
+	opts.SkipDirs = nil
+	opts.StdinFilename = ""
+	opts.Baseline = nil
+	opts.Changes = nil
+	opts.Jobs = 0
+	opts.ReadFile = nil
+	opts.Warn = nil
+	opts.Reporter = nil
+	opts.Cache = nil
+	custom := ""
+	for _, c := range CustomChecks() {
+		custom += c.Name() + ","
+	}
+	funcs := fmt.Sprintf("%v %v", opts.IgnoreFuncs, opts.OnlyFuncs)
+	opts.IgnoreFuncs, opts.OnlyFuncs = nil, nil
+	return fmt.Sprintf("%+v %s %s", opts, custom, funcs)
+}
+
+func (c *Cache) load(path string, r *fileResult) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return false
+	}
+	for _, co := range e.Found {
+		co.Offender.message = co.Message
+		r.found = append(r.found, co.Offender)
+	}
+	r.summary = &Summary{
+		Suppressed:     e.Suppressed,
+		Overrides:      e.Overrides,
+		NumOverrides:   len(e.Overrides),
+		Packages:       e.Packages,
+		Functions:      e.Functions,
+		NumGenerated:   e.NumGenerated,
+		NumConstrained: e.NumConstrained,
+		NumCgo:         e.NumCgo,
+		Markers:        e.Markers,
+		types:          e.Types,
+		shapes:         e.Shapes,
+		callSites:      e.CallSites,
+		complexFuncs:   e.ComplexFuncs,
+	}
+	return true
+}
+
+func (c *Cache) store(path string, r *fileResult) {
+	e := cacheEntry{
+		Suppressed:     r.summary.Suppressed,
+		Overrides:      r.summary.Overrides,
+		Packages:       r.summary.Packages,
+		Functions:      r.summary.Functions,
+		Markers:        r.summary.Markers,
+		Types:          r.summary.types,
+		Shapes:         r.summary.shapes,
+		CallSites:      r.summary.callSites,
+		ComplexFuncs:   r.summary.complexFuncs,
+		NumGenerated:   r.summary.NumGenerated,
+		NumConstrained: r.summary.NumConstrained,
+		NumCgo:         r.summary.NumCgo,
+	}
+	for _, o := range r.found {
+		e.Found = append(e.Found, cachedOffender{o, o.message})
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	// write and rename, so concurrent runs never read half an entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,127 @@
+package lint
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestCachePath(t *testing.T) {
+	src := []byte("package a\n")
+	tests := []struct {
+		name    string
+		change  func(filename *string, src *[]byte, opts *Options)
+		changed bool
+	}{
+		{"nothing", func(*string, *[]byte, *Options) {}, false},
+		{"contents", func(_ *string, src *[]byte, _ *Options) { *src = []byte("package b\n") }, true},
+		{"filename", func(filename *string, _ *[]byte, _ *Options) { *filename = "b.go" }, true},
+		{"threshold", func(_ *string, _ *[]byte, opts *Options) { opts.StatementThreshold++ }, true},
+		{"ignored functions", func(_ *string, _ *[]byte, opts *Options) { opts.IgnoreFuncs = regexp.MustCompile("^init$") }, true},
+		{"skipped directories", func(_ *string, _ *[]byte, opts *Options) { opts.SkipDirs = nil }, false},
+		{"jobs", func(_ *string, _ *[]byte, opts *Options) { opts.Jobs = 8 }, false},
+		{"baseline", func(_ *string, _ *[]byte, opts *Options) { opts.Baseline = new(Baseline) }, false},
+	}
+	c := &Cache{dir: t.TempDir()}
+	want := c.path("a.go", src, DefaultOptions())
+	for _, tt := range tests {
+		filename, changed, opts := "a.go", src, DefaultOptions()
+		tt.change(&filename, &changed, &opts)
+		if got := c.path(filename, changed, opts); (got != want) != tt.changed {
+			t.Errorf("%s: key changed is %v, want %v", tt.name, got != want, tt.changed)
+		}
+	}
+}
+
+func TestCacheReuse(t *testing.T) {
+	filename := writeSource(t, "package a\n\nfunc f() {\n\ta := 1\n\t_ = a\n}\n")
+	cache, err := OpenCache(filepath.Join(filepath.Dir(filename), "cache"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := func(*Options) {
+		if err := os.WriteFile(filename, []byte("package a\n\nfunc f() {}\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name   string
+		change func(opts *Options)
+		hits   int
+		misses int
+		issues int
+	}{
+		{"first run", func(*Options) {}, 0, 1, 1},
+		{"unchanged", func(*Options) {}, 1, 1, 1},
+		{"new threshold", func(opts *Options) { opts.StatementThreshold = 5 }, 1, 2, 0},
+		{"edited file", edit, 1, 3, 0},
+		{"edited file again", func(*Options) {}, 2, 3, 0},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.StatementThreshold = 1
+		opts.Cache = cache
+		tt.change(&opts)
+		issues := countIssues(run(t, filename, opts))
+		if hits, misses := cache.Stats(); hits != tt.hits || misses != tt.misses || issues != tt.issues {
+			t.Errorf("%s: %d hits, %d misses, %d issues, want %d, %d, %d",
+				tt.name, hits, misses, issues, tt.hits, tt.misses, tt.issues)
+		}
+	}
+}
+
+// TestCacheFanIn checks that the call sites and complex functions the
+// fan-in check needs survive the cache.
+func TestCacheFanIn(t *testing.T) {
+	c := &Cache{dir: t.TempDir()}
+	path := c.path("a.go", []byte("package a\n"), DefaultOptions())
+	complex := &complexFunc{Offender: &Offender{Check: CheckFanIn, Function: "f"}, lateIgnore: lateIgnore{true, "legacy"}}
+	r := &fileResult{summary: &Summary{
+		callSites:    map[string]int{"a.f": 3, "a.g": 1},
+		complexFuncs: map[string]*complexFunc{"a.f": complex},
+	}}
+	c.store(path, r)
+	loaded := new(fileResult)
+	if !c.load(path, loaded) {
+		t.Fatal("stored result not loaded")
+	}
+	if !maps.Equal(loaded.summary.callSites, r.summary.callSites) {
+		t.Errorf("call sites = %v, want %v", loaded.summary.callSites, r.summary.callSites)
+	}
+	got := loaded.summary.complexFuncs["a.f"]
+	if len(loaded.summary.complexFuncs) != 1 || got == nil || got.Offender.Function != "f" || got.lateIgnore != complex.lateIgnore {
+		t.Errorf("complex functions = %v, want %v", loaded.summary.complexFuncs, r.summary.complexFuncs)
+	}
+}
+
+// writeSource writes src to a.go in a temporary directory, and returns its
+// name.
+func writeSource(t *testing.T, src string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "a.go")
+	if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+// run runs splint on a file, failing the test if it cannot.
+func run(t *testing.T, filename string, opts Options) *Summary {
+	t.Helper()
+	summary, err := Run([]string{filename}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return summary
+}
+
+// countIssues counts the offenders of every check in a summary.
+func countIssues(s *Summary) int {
+	n := 0
+	for _, section := range s.Sections() {
+		n += len(section.Offenders)
+	}
+	return n
+}
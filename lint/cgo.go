@@ -0,0 +1,24 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// importsC reports whether a file uses cgo.
+func importsC(tree *ast.File) bool {
+	for _, imp := range tree.Imports {
+		if imp.Path.Value == `"C"` {
+			return true
+		}
+	}
+	return false
+}
+
+// rewrittenByCgo reports whether a file loaded by the go tool is the
+// output of cgo rather than the file it was generated from, which its
+// //line directive names.  Its code differs from the original, which
+// leaves its positions wrong.
+func rewrittenByCgo(fileset *token.FileSet, tree *ast.File) bool {
+	return fileset.File(tree.Pos()).Name() != fileset.Position(tree.Pos()).Filename
+}
@@ -0,0 +1,42 @@
+package lint
+
+import "go/ast"
+
+// callChain returns the number of calls chained by selectors in an
+// expression, like 3 for a.B().C().D(), and the calls inside the chain.
+func callChain(e ast.Expr) (int, []*ast.CallExpr) {
+	var calls []*ast.CallExpr
+	for {
+		call, ok := e.(*ast.CallExpr)
+		if !ok {
+			return len(calls), calls
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return len(calls), calls
+		}
+		calls = append(calls, call)
+		e = sel.X
+	}
+}
+
+// checkMethodChains looks for chains of too many calls, like fluent
+// builders, which hide much of what a statement does.  Each chain is
+// reported once, at its start, rather than for every call in it.
+func (p *Parser) checkMethodChains(x *ast.FuncDecl) {
+	inChain := make(map[*ast.CallExpr]bool)
+	inspectFunc(x, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || inChain[call] {
+			return true
+		}
+		n, calls := callChain(call)
+		for _, c := range calls {
+			inChain[c] = true
+		}
+		if n > p.opts.ChainThreshold {
+			p.report(CheckMethodChain, p.offender(funcName(x), n, call), p.summary.addMethodChain)
+		}
+		return true
+	})
+}
@@ -0,0 +1,530 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// Names of the checks, as used in directives and on the command line.
+const (
+	CheckStatementCount      = "statement-count"
+	CheckLineCount           = "line-count"
+	CheckInitLength          = "init-length"
+	CheckParamCount          = "param-count"
+	CheckVariadic            = "variadic"
+	CheckContext             = "context-param"
+	CheckResultCount         = "result-count"
+	CheckReturnCount         = "return-count"
+	CheckLocalCount          = "local-count"
+	CheckIfChain             = "if-chain"
+	CheckEmptyIf             = "empty-if"
+	CheckEmptyElse           = "empty-else"
+	CheckEmptyFor            = "empty-for"
+	CheckEmptySwitch         = "empty-switch"
+	CheckEmptySelect         = "empty-select"
+	CheckCondition           = "condition-complexity"
+	CheckGuardClause         = "guard-clause"
+	CheckNamedResults        = "named-results"
+	CheckMethodChain         = "method-chain"
+	CheckGoroutines          = "goroutine-count"
+	CheckGoInLoop            = "goroutine-in-loop"
+	CheckPanics              = "panic-recover"
+	CheckExit                = "exit-outside-main"
+	CheckFanOut              = "fan-out"
+	CheckFanIn               = "fan-in"
+	CheckUnreachable         = "unreachable"
+	CheckSwitchDefault       = "switch-default"
+	CheckTypeSwitchCases     = "type-switch-cases"
+	CheckClosureDepth        = "closure-depth"
+	CheckMixedReceivers      = "mixed-receivers"
+	CheckLongIf              = "long-if"
+	CheckSwitchCases         = "switch-cases"
+	CheckLongCase            = "long-case"
+	CheckLabels              = "labels"
+	CheckDiscardedError      = "discarded-error"
+	CheckMagicNumber         = "magic-number"
+	CheckBoolParam           = "bool-param"
+	CheckCognitiveComplexity = "cognitive-complexity"
+	CheckDuplicate           = "duplicate"
+	CheckCommentDensity      = "comment-density"
+	CheckHalstead            = "halstead"
+	CheckMaintainability     = "maintainability"
+	CheckFileLength          = "file-length"
+	CheckFileFunctions       = "file-functions"
+	CheckImports             = "import-count"
+	CheckGlobals             = "global-vars"
+	CheckStructFields        = "struct-fields"
+	CheckInterfaceMethods    = "interface-methods"
+	CheckTypeMethods         = "type-methods"
+	CheckLineLength          = "line-length"
+	CheckMarkers             = "todo-markers"
+)
+
+// checkIDs holds the stable identifiers of the built-in checks.  An ID is
+// never changed or reused, so new checks take the next number.
+var checkIDs = map[string]string{
+	CheckStatementCount:      "SPL001",
+	CheckParamCount:          "SPL002",
+	CheckResultCount:         "SPL003",
+	CheckIfChain:             "SPL004",
+	CheckEmptyIf:             "SPL005",
+	CheckLongIf:              "SPL006",
+	CheckBoolParam:           "SPL007",
+	CheckCognitiveComplexity: "SPL008",
+	CheckLineCount:           "SPL009",
+	CheckFileLength:          "SPL010",
+	CheckFileFunctions:       "SPL011",
+	CheckStructFields:        "SPL012",
+	CheckInterfaceMethods:    "SPL013",
+	CheckReturnCount:         "SPL014",
+	CheckLocalCount:          "SPL015",
+	CheckSwitchCases:         "SPL016",
+	CheckLongCase:            "SPL017",
+	CheckLabels:              "SPL018",
+	CheckMagicNumber:         "SPL019",
+	CheckLineLength:          "SPL020",
+	CheckTypeMethods:         "SPL021",
+	CheckVariadic:            "SPL022",
+	CheckContext:             "SPL023",
+	CheckImports:             "SPL024",
+	CheckGlobals:             "SPL025",
+	CheckInitLength:          "SPL026",
+	CheckDuplicate:           "SPL027",
+	CheckCommentDensity:      "SPL028",
+	CheckMarkers:             "SPL029",
+	CheckHalstead:            "SPL030",
+	CheckMaintainability:     "SPL031",
+	CheckDiscardedError:      "SPL032",
+	CheckEmptyElse:           "SPL033",
+	CheckEmptyFor:            "SPL034",
+	CheckEmptySwitch:         "SPL035",
+	CheckEmptySelect:         "SPL036",
+	CheckCondition:           "SPL037",
+	CheckGuardClause:         "SPL038",
+	CheckNamedResults:        "SPL039",
+	CheckMethodChain:         "SPL040",
+	CheckGoroutines:          "SPL041",
+	CheckGoInLoop:            "SPL042",
+	CheckPanics:              "SPL043",
+	CheckExit:                "SPL044",
+	CheckFanOut:              "SPL045",
+	CheckFanIn:               "SPL046",
+	CheckUnreachable:         "SPL047",
+	CheckSwitchDefault:       "SPL048",
+	CheckTypeSwitchCases:     "SPL049",
+	CheckClosureDepth:        "SPL050",
+	CheckMixedReceivers:      "SPL051",
+}
+
+// CheckID returns the stable identifier of a check, like "SPL001" for
+// statement-count.  Custom checks have none.
+func CheckID(check string) string {
+	return checkIDs[check]
+}
+
+// Checks returns the names of all the checks, in the order of
+// Summary.Sections.
+func Checks() []string {
+	var names []string
+	for _, section := range new(Summary).Sections() {
+		names = append(names, section.Check)
+	}
+	return names
+}
+
+// shortName is the check name without dashes or a trailing "-count" or
+// "-complexity", like "ifchain" for "if-chain".
+func shortName(check string) string {
+	check = strings.TrimSuffix(check, "-count")
+	check = strings.TrimSuffix(check, "-complexity")
+	return strings.ReplaceAll(check, "-", "")
+}
+
+// LookupCheck resolves a check name, its short name like "statement" for
+// "statement-count", or its ID like "SPL001", and reports whether the
+// check exists.
+func LookupCheck(name string) (string, bool) {
+	for _, check := range Checks() {
+		if name == check || name == shortName(check) || hasID(check, name) {
+			return check, true
+		}
+	}
+	return "", false
+}
+
+// hasID reports whether id is the ID of check, in any case.
+func hasID(check, id string) bool {
+	return CheckID(check) != "" && strings.EqualFold(CheckID(check), id)
+}
+
+// inspectFunc is ast.Inspect for a function, leaving out the function
+// literals, which are checked on their own.
+func inspectFunc(x *ast.FuncDecl, f func(ast.Node) bool) {
+	ast.Inspect(x, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok {
+			return false
+		}
+		return f(node)
+	})
+}
+
+// statementCount counts the statements below a node, leaving out those of
+// function literals, which are checked on their own.
+func statementCount(n ast.Node) int {
+	total := 0
+	counter := func(node ast.Node) bool {
+		switch node.(type) {
+		case *ast.FuncLit:
+			return false
+		case ast.Stmt:
+			total++
+		}
+		return true
+	}
+	ast.Inspect(n, counter)
+	return total
+}
+
+func (p *Parser) checkFuncLength(x *ast.FuncDecl) {
+	numStatements := statementCount(x)
+	if numStatements <= p.opts.StatementThreshold {
+		return
+	}
+
+	o := p.offender(funcName(x), numStatements, x)
+	o.Suggestion = p.suggestExtract(x)
+	p.report(CheckStatementCount, o, p.summary.addStatement)
+}
+
+// lineCount returns the number of source lines a node spans.
+func (p *Parser) lineCount(n ast.Node) int {
+	return p.fileset.Position(n.End()).Line - p.fileset.Position(n.Pos()).Line + 1
+}
+
+func (p *Parser) checkFuncLines(x *ast.FuncDecl) {
+	if p.opts.LineThreshold <= 0 {
+		return
+	}
+	numLines := p.lineCount(x)
+	if numLines <= p.opts.LineThreshold {
+		return
+	}
+
+	p.report(CheckLineCount, p.offender(funcName(x), numLines, x), p.summary.addLines)
+}
+
+func (p *Parser) checkParamCount(x *ast.FuncDecl) {
+	numFields := x.Type.Params.NumFields()
+	if numFields <= p.opts.ParamThreshold {
+		return
+	}
+
+	o := p.offender(funcName(x), numFields, x)
+	o.ParamObject = p.suggestParamObject(x)
+	p.report(CheckParamCount, o, p.summary.addParam)
+}
+
+// checkBoolParams reports every bool parameter, including pointers to
+// bools, slices of bools and variadic bools.  With type information, named
+// types with an underlying bool type are reported too.
+func (p *Parser) checkBoolParams(x *ast.FuncDecl) {
+	for _, f := range x.Type.Params.List {
+		if len(f.Names) == 0 {
+			if p.isBool(f.Type, nil) {
+				p.report(CheckBoolParam, p.offender(funcName(x), 0, f), p.summary.addBoolParam)
+			}
+			continue
+		}
+		for _, name := range f.Names {
+			if p.isBool(f.Type, name) {
+				p.report(CheckBoolParam, p.offender(funcName(x), 0, name), p.summary.addBoolParam)
+			}
+		}
+	}
+}
+
+// isBool reports whether a parameter of the given type, and name if it
+// has one, holds bools.
+func (p *Parser) isBool(typ ast.Expr, name *ast.Ident) bool {
+	if p.info != nil {
+		var t types.Type
+		if obj := p.info.Defs[name]; obj != nil {
+			t = obj.Type()
+		} else {
+			t = p.info.TypeOf(typ)
+		}
+		if t != nil {
+			return isBoolType(t)
+		}
+	}
+	for {
+		switch e := typ.(type) {
+		case *ast.StarExpr:
+			typ = e.X
+		case *ast.Ellipsis:
+			typ = e.Elt
+		case *ast.ArrayType:
+			typ = e.Elt
+		case *ast.ParenExpr:
+			typ = e.X
+		case *ast.Ident:
+			return e.Name == "bool"
+		default:
+			return false
+		}
+	}
+}
+
+func isBoolType(t types.Type) bool {
+	for {
+		switch u := t.Underlying().(type) {
+		case *types.Pointer:
+			t = u.Elem()
+		case *types.Slice:
+			t = u.Elem()
+		case *types.Array:
+			t = u.Elem()
+		case *types.Basic:
+			return u.Info()&types.IsBoolean != 0
+		default:
+			return false
+		}
+	}
+}
+
+func (p *Parser) checkResultCount(x *ast.FuncDecl) {
+	numResults := x.Type.Results.NumFields()
+	if numResults <= p.opts.ResultThreshold {
+		return
+	}
+
+	p.report(CheckResultCount, p.offender(funcName(x), numResults, x), p.summary.addResult)
+}
+
+// checkEmptyIfs looks for if statements with an empty or long body, and
+// for else branches with no statements, maybe holding a comment.
+func (p *Parser) checkEmptyIfs(x *ast.FuncDecl) {
+	findIf := func(node ast.Node) bool {
+		switch y := node.(type) {
+		case *ast.IfStmt:
+			if y.Body == nil || len(y.Body.List) == 0 {
+				p.report(CheckEmptyIf, p.offender(funcName(x), 0, y), p.summary.addEmptyIfBody)
+			} else if statementCount(y.Body) > p.opts.IfBodyThreshold {
+				p.report(CheckLongIf, p.offender(funcName(x), 0, y), p.summary.addLongIfBody)
+			}
+			if block, ok := y.Else.(*ast.BlockStmt); ok && len(block.List) == 0 {
+				p.report(CheckEmptyElse, p.offender(funcName(x), 0, block), p.summary.addEmptyElse)
+			}
+		}
+		return true
+	}
+	inspectFunc(x, findIf)
+}
+
+func (p *Parser) checkCognitive(x *ast.FuncDecl) {
+	complexity := cognitiveComplexity(x)
+	if complexity <= p.opts.CognitiveThreshold {
+		return
+	}
+
+	p.report(CheckCognitiveComplexity, p.offender(funcName(x), complexity, x), p.summary.addCognitive)
+}
+
+// countNodes counts the nodes of a function that match, leaving out the
+// ones of function literals.
+func countNodes(x *ast.FuncDecl, match func(ast.Node) bool) int {
+	total := 0
+	inspectFunc(x, func(node ast.Node) bool {
+		if match(node) {
+			total++
+		}
+		return true
+	})
+	return total
+}
+
+// returnCount counts the return statements of a function, leaving out
+// the ones of function literals.
+func returnCount(x *ast.FuncDecl) int {
+	return countNodes(x, func(node ast.Node) bool {
+		_, ok := node.(*ast.ReturnStmt)
+		return ok
+	})
+}
+
+func (p *Parser) checkReturnCount(x *ast.FuncDecl) {
+	numReturns := returnCount(x)
+	if numReturns <= p.opts.ReturnThreshold {
+		return
+	}
+
+	p.report(CheckReturnCount, p.offender(funcName(x), numReturns, x), p.summary.addReturn)
+}
+
+// localNames collects the distinct names of local variables.
+type localNames map[string]bool
+
+func (names localNames) add(exprs ...ast.Expr) {
+	for _, e := range exprs {
+		if id, ok := e.(*ast.Ident); ok && id.Name != "_" {
+			names[id.Name] = true
+		}
+	}
+}
+
+// visit adds the variables declared by a node with var, := or range,
+// leaving out function literals.
+func (names localNames) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.FuncLit:
+		return false
+	case *ast.ValueSpec:
+		for _, id := range n.Names {
+			names.add(id)
+		}
+	case *ast.AssignStmt:
+		if n.Tok == token.DEFINE {
+			names.add(n.Lhs...)
+		}
+	case *ast.RangeStmt:
+		if n.Tok == token.DEFINE {
+			names.add(n.Key, n.Value)
+		}
+	}
+	return true
+}
+
+func localCount(x *ast.FuncDecl) int {
+	names := make(localNames)
+	if x.Body != nil {
+		ast.Inspect(x.Body, names.visit)
+	}
+	return len(names)
+}
+
+func (p *Parser) checkLocalCount(x *ast.FuncDecl) {
+	numLocals := localCount(x)
+	if numLocals <= p.opts.LocalThreshold {
+		return
+	}
+
+	p.report(CheckLocalCount, p.offender(funcName(x), numLocals, x), p.summary.addLocals)
+}
+
+func chainLength(x *ast.IfStmt) int {
+	if x.Else == nil {
+		return 0
+	}
+	if ifst, ok := x.Else.(*ast.IfStmt); ok {
+		return 1 + chainLength(ifst)
+	}
+	return 1
+}
+
+func (p *Parser) checkIfChains(x *ast.FuncDecl) {
+	findIf := func(node ast.Node) bool {
+		switch y := node.(type) {
+		case *ast.IfStmt:
+			n := chainLength(y)
+			if n > p.opts.IfChainThreshold {
+				p.report(CheckIfChain, p.offender(funcName(x), n, y), p.summary.addIfChain)
+			}
+			return false // don't go any deeper
+		}
+		return true
+	}
+	inspectFunc(x, findIf)
+}
+
+// checkSwitchCases counts the case clauses of every switch statement and
+// type switch, including the default clause.  Type switches are held to a
+// threshold of their own, as one with many cases usually stands for a
+// missing method.
+func (p *Parser) checkSwitchCases(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		switch y := node.(type) {
+		case *ast.SwitchStmt:
+			if n := len(y.Body.List); n > p.opts.SwitchCaseThreshold {
+				p.report(CheckSwitchCases, p.offender(funcName(x), n, y), p.summary.addSwitch)
+			}
+		case *ast.TypeSwitchStmt:
+			if n := len(y.Body.List); n > p.opts.TypeSwitchCaseThreshold {
+				p.report(CheckTypeSwitchCases, p.offender(funcName(x), n, y), p.summary.addTypeSwitch)
+			}
+		}
+		return true
+	})
+}
+
+// checkLongCases looks for the case clauses of switch and select
+// statements with too long a body.
+func (p *Parser) checkLongCases(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		var body []ast.Stmt
+		switch y := node.(type) {
+		case *ast.CaseClause:
+			body = y.Body
+		case *ast.CommClause:
+			body = y.Body
+		default:
+			return true
+		}
+		n := 0
+		for _, stmt := range body {
+			n += statementCount(stmt)
+		}
+		if n > p.opts.CaseBodyThreshold {
+			p.report(CheckLongCase, p.offender(funcName(x), n, node), p.summary.addLongCase)
+		}
+		return true
+	})
+}
+
+// labelCount counts the goto statements, labeled statements, and labeled
+// break and continue statements of a function, leaving out function
+// literals.
+func labelCount(x *ast.FuncDecl) int {
+	total := 0
+	inspectFunc(x, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.LabeledStmt:
+			total++
+		case *ast.BranchStmt:
+			if n.Tok == token.GOTO || n.Label != nil {
+				total++
+			}
+		}
+		return true
+	})
+	return total
+}
+
+func (p *Parser) checkLabels(x *ast.FuncDecl) {
+	numLabels := labelCount(x)
+	if numLabels <= p.opts.LabelThreshold {
+		return
+	}
+
+	p.report(CheckLabels, p.offender(funcName(x), numLabels, x), p.summary.addLabels)
+}
+
+func isInit(x *ast.FuncDecl) bool {
+	return x.Recv == nil && x.Name.Name == "init"
+}
+
+// checkInit holds init functions to a lower statement threshold, and
+// counts them in their package.
+func (p *Parser) checkInit(x *ast.FuncDecl) {
+	if !isInit(x) {
+		return
+	}
+	p.summary.pkg(p.pkgPath).Inits++
+	numStatements := statementCount(x)
+	if numStatements <= p.opts.InitThreshold {
+		return
+	}
+
+	p.report(CheckInitLength, p.offender(funcName(x), numStatements, x), p.summary.addInit)
+}
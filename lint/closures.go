@@ -0,0 +1,119 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+)
+
+// closures names the function literals found below a function or a
+// package level variable, and runs the checks on each of them.  Literals
+// assigned to a variable are named after it; the others are numbered the
+// way the go compiler does, like "main.func1" and "main.func1.1", and
+// after the variable holding them at the package level.
+type closures struct {
+	p       *Parser
+	prefix  string
+	numbers string // "func" for the literals of a declaration
+	count   int
+	names   map[*ast.FuncLit]string
+	global  string // package level variable holding the literals
+	depth   int    // number of literals holding the literals
+}
+
+func (p *Parser) examineClosures(prefix string, root ast.Node) {
+	c := &closures{p: p, prefix: prefix, numbers: "func", names: make(map[*ast.FuncLit]string)}
+	ast.Inspect(root, c.visit)
+}
+
+// name returns the name of a literal found below the prefix.
+func (c *closures) name(lit *ast.FuncLit) string {
+	if name, ok := c.names[lit]; ok {
+		return join(c.prefix, name)
+	}
+	c.count++
+	prefix := c.prefix
+	if prefix == "" {
+		prefix = c.global
+	}
+	return join(prefix, fmt.Sprintf("%s%d", c.numbers, c.count))
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+// assign remembers the names of the variables literals are assigned to.
+func (c *closures) assign(lhs []*ast.Ident, rhs []ast.Expr) {
+	if len(lhs) != len(rhs) {
+		return
+	}
+	for i, e := range rhs {
+		if lit, ok := e.(*ast.FuncLit); ok && lhs[i].Name != "_" {
+			c.names[lit] = lhs[i].Name
+		}
+	}
+}
+
+func (c *closures) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.ValueSpec:
+		if c.prefix == "" && len(n.Names) > 0 {
+			c.global = n.Names[0].Name
+		}
+		c.assign(n.Names, n.Values)
+	case *ast.AssignStmt:
+		var lhs []*ast.Ident
+		for _, e := range n.Lhs {
+			if id, ok := e.(*ast.Ident); ok {
+				lhs = append(lhs, id)
+			}
+		}
+		c.assign(lhs, n.Rhs)
+	case *ast.FuncLit:
+		name := c.name(n)
+		c.p.examineClosure(name, n)
+		c.p.checkClosureDepth(name, n, c.depth+1)
+		nested := &closures{p: c.p, prefix: name, names: make(map[*ast.FuncLit]string), depth: c.depth + 1}
+		ast.Inspect(n.Body, nested.visit)
+		return false
+	}
+	return true
+}
+
+// holdsClosure reports whether a function literal holds another one.
+func holdsClosure(lit *ast.FuncLit) bool {
+	found := false
+	ast.Inspect(lit.Body, func(node ast.Node) bool {
+		if _, ok := node.(*ast.FuncLit); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// checkClosureDepth looks for function literals nested in more literals
+// than opts.ClosureDepthThreshold, reporting only the innermost ones of a
+// pyramid of callbacks.  Depth counts the literal itself.
+func (p *Parser) checkClosureDepth(name string, lit *ast.FuncLit, depth int) {
+	if depth <= p.opts.ClosureDepthThreshold || holdsClosure(lit) {
+		return
+	}
+
+	p.report(CheckClosureDepth, p.offender(name, depth, lit), p.summary.addClosureDepth)
+}
+
+// examineClosure runs the function checks on a function literal, as if it
+// was a function declared with the given name.  Closures are not counted
+// as functions of their package, since their statements already count
+// towards the function holding them.
+func (p *Parser) examineClosure(name string, lit *ast.FuncLit) {
+	p.runChecks(&ast.FuncDecl{
+		Name: &ast.Ident{NamePos: lit.Pos(), Name: name},
+		Type: lit.Type,
+		Body: lit.Body,
+	})
+}
@@ -0,0 +1,163 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// cognitive computes the SonarSource cognitive complexity of a function:
+// every break in the linear flow of the code costs one, and control flow
+// structures cost one more for each level they are nested at.
+type cognitive struct {
+	total int
+	fn    *ast.FuncDecl
+}
+
+func cognitiveComplexity(x *ast.FuncDecl) int {
+	c := &cognitive{fn: x}
+	if x.Body != nil {
+		c.walk(x.Body, 0)
+	}
+	return c.total
+}
+
+func (c *cognitive) walk(n ast.Node, nesting int) {
+	ast.Walk(&cognitiveVisitor{c, nesting}, n)
+}
+
+type cognitiveVisitor struct {
+	c       *cognitive
+	nesting int
+}
+
+// same walks the given nodes at the nesting level of v, skipping nils.
+func (v *cognitiveVisitor) same(nodes ...ast.Node) {
+	for _, n := range nodes {
+		if n != nil {
+			ast.Walk(v, n)
+		}
+	}
+}
+
+// nested walks a block one level deeper than v.
+func (v *cognitiveVisitor) nested(body *ast.BlockStmt) {
+	v.c.walk(body, v.nesting+1)
+}
+
+func (v *cognitiveVisitor) Visit(node ast.Node) ast.Visitor {
+	if v.visitFlow(node) {
+		return nil
+	}
+	switch x := node.(type) {
+	case *ast.FuncLit:
+		v.nested(x.Body)
+		return nil
+	case *ast.BranchStmt:
+		if x.Tok == token.GOTO || x.Label != nil {
+			v.c.total++
+		}
+	case *ast.BinaryExpr:
+		if isLogical(x.Op) {
+			v.visitLogical(x)
+			return nil
+		}
+	case *ast.CallExpr:
+		if v.c.isRecursive(x) {
+			v.c.total++
+		}
+	}
+	return v
+}
+
+// visitFlow walks a control flow structure, which costs one plus its
+// nesting level.  It reports whether node was one.
+func (v *cognitiveVisitor) visitFlow(node ast.Node) bool {
+	switch x := node.(type) {
+	case *ast.IfStmt:
+		v.visitIf(x)
+	case *ast.ForStmt:
+		v.same(x.Init, x.Cond, x.Post)
+		v.nested(x.Body)
+	case *ast.RangeStmt:
+		v.same(x.Key, x.Value, x.X)
+		v.nested(x.Body)
+	case *ast.SwitchStmt:
+		v.same(x.Init, x.Tag)
+		v.nested(x.Body)
+	case *ast.TypeSwitchStmt:
+		v.same(x.Init, x.Assign)
+		v.nested(x.Body)
+	case *ast.SelectStmt:
+		v.nested(x.Body)
+	default:
+		return false
+	}
+	v.c.total += 1 + v.nesting
+	return true
+}
+
+// visitIf walks an if statement whose own increment has already been
+// counted.  else and else if branches cost one each, regardless of nesting.
+func (v *cognitiveVisitor) visitIf(x *ast.IfStmt) {
+	v.same(x.Init, x.Cond)
+	v.nested(x.Body)
+	switch e := x.Else.(type) {
+	case *ast.IfStmt:
+		v.c.total++
+		v.visitIf(e)
+	case *ast.BlockStmt:
+		v.c.total++
+		v.nested(e)
+	}
+}
+
+// visitLogical counts one for each sequence of like boolean operators in
+// an expression, so "a && b && c" costs one and "a && b || c" costs two.
+func (v *cognitiveVisitor) visitLogical(x *ast.BinaryExpr) {
+	var ops []token.Token
+	var operands []ast.Expr
+	var flatten func(e ast.Expr)
+	flatten = func(e ast.Expr) {
+		if p, ok := e.(*ast.ParenExpr); ok {
+			flatten(p.X)
+			return
+		}
+		if b, ok := e.(*ast.BinaryExpr); ok && isLogical(b.Op) {
+			flatten(b.X)
+			ops = append(ops, b.Op)
+			flatten(b.Y)
+			return
+		}
+		operands = append(operands, e)
+	}
+	flatten(x)
+
+	for i, op := range ops {
+		if i == 0 || op != ops[i-1] {
+			v.c.total++
+		}
+	}
+	for _, e := range operands {
+		v.same(e)
+	}
+}
+
+func isLogical(op token.Token) bool {
+	return op == token.LAND || op == token.LOR
+}
+
+// isRecursive reports whether a call is a direct call of the function being
+// measured, either by name or, for methods, through the receiver.
+func (c *cognitive) isRecursive(call *ast.CallExpr) bool {
+	switch f := call.Fun.(type) {
+	case *ast.Ident:
+		return c.fn.Recv == nil && f.Name == c.fn.Name.Name
+	case *ast.SelectorExpr:
+		if c.fn.Recv == nil || len(c.fn.Recv.List) == 0 || len(c.fn.Recv.List[0].Names) == 0 {
+			return false
+		}
+		recv, ok := f.X.(*ast.Ident)
+		return ok && f.Sel.Name == c.fn.Name.Name && recv.Name == c.fn.Recv.List[0].Names[0].Name
+	}
+	return false
+}
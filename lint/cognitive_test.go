@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestCognitiveComplexity(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want int
+	}{
+		{"straight", "func f() {\n\tg()\n}", 0},
+		{"if", "func f(a bool) {\n\tif a {\n\t}\n}", 1},
+		{"if else", "func f(a bool) {\n\tif a {\n\t} else {\n\t}\n}", 2},
+		{"else if", "func f(a, b bool) {\n\tif a {\n\t} else if b {\n\t} else {\n\t}\n}", 3},
+		{"nested", "func f(a bool) {\n\tfor {\n\t\tif a {\n\t\t\tfor range 3 {\n\t\t\t}\n\t\t}\n\t}\n}", 6},
+		{"switch", "func f(n int) {\n\tswitch n {\n\tcase 1:\n\tcase 2:\n\tdefault:\n\t}\n}", 1},
+		{"select", "func f(c chan int) {\n\tselect {\n\tcase <-c:\n\t}\n}", 1},
+		{"type switch", "func f(x any) {\n\tswitch x.(type) {\n\tcase int:\n\t}\n}", 1},
+		{"like operators", "func f(a, b, c bool) bool {\n\treturn a && b && c\n}", 1},
+		{"mixed operators", "func f(a, b, c bool) bool {\n\treturn a && b || c\n}", 2},
+		{"parenthesized", "func f(a, b, c bool) bool {\n\treturn a && (b && c)\n}", 1},
+		{"condition", "func f(a, b bool) {\n\tif a || b {\n\t}\n}", 2},
+		{"labeled break", "func f() {\nL:\n\tfor {\n\t\tbreak L\n\t}\n}", 2},
+		{"plain break", "func f() {\n\tfor {\n\t\tbreak\n\t}\n}", 1},
+		{"goto", "func f() {\nL:\n\tgoto L\n}", 1},
+		{"recursion", "func f(n int) int {\n\treturn f(n - 1)\n}", 1},
+		{"method recursion", "func (t *T) f() {\n\tt.f()\n\tu.f()\n}", 1},
+		{"function literal", "func f(a bool) {\n\tg(func() {\n\t\tif a {\n\t\t}\n\t})\n}", 2},
+	}
+	for _, tt := range tests {
+		f, err := parser.ParseFile(token.NewFileSet(), "a.go", "package a\n\n"+tt.src+"\n", 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := cognitiveComplexity(f.Decls[0].(*ast.FuncDecl)); got != tt.want {
+			t.Errorf("%s: cognitive complexity %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package lint
+
+import "go/ast"
+
+// commentLines counts the lines of the comments inside the body of a
+// function, leaving out its doc comment.
+func (p *Parser) commentLines(x *ast.FuncDecl) int {
+	if x.Body == nil {
+		return 0
+	}
+	total := 0
+	for _, cg := range p.comments {
+		if cg.Pos() < x.Body.Lbrace || cg.End() > x.Body.Rbrace {
+			continue
+		}
+		for _, c := range cg.List {
+			total += p.lineCount(c)
+		}
+	}
+	return total
+}
+
+// commentRatio is the number of comment lines per statement of a
+// function.
+func (p *Parser) commentRatio(x *ast.FuncDecl) float64 {
+	n := statementCount(x)
+	if n == 0 {
+		return 0
+	}
+	return float64(p.commentLines(x)) / float64(n)
+}
+
+// checkComments reports the functions with more than
+// opts.CommentThreshold statements and fewer comment lines per statement
+// than opts.CommentRatio.
+func (p *Parser) checkComments(x *ast.FuncDecl) {
+	if p.opts.CommentThreshold <= 0 || statementCount(x) <= p.opts.CommentThreshold {
+		return
+	}
+	if p.commentRatio(x) >= p.opts.CommentRatio {
+		return
+	}
+
+	p.report(CheckCommentDensity, p.offender(funcName(x), p.commentLines(x), x), p.summary.addComments)
+}
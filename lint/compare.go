@@ -0,0 +1,89 @@
+package lint
+
+// Comparison holds the differences between the issues of an old and a new
+// summary.  Issues are matched the way baselines match them, by check,
+// file, function and type, so that they survive unrelated edits.
+type Comparison struct {
+	New       []*Offender
+	Fixed     []*Offender
+	Unchanged []*Change
+
+	// Checks holds the number of issues of each check before and after,
+	// and Packages the rollup of each package before and after, nil
+	// where the package is missing.
+	Checks   map[string][2]int
+	Packages map[string][2]*PackageSummary
+}
+
+// Change is an issue found in both summaries, with its count before and
+// after.
+type Change struct {
+	Old, New *Offender
+}
+
+// Delta is how much the count of an issue grew.
+func (c *Change) Delta() int {
+	return c.New.Count - c.Old.Count
+}
+
+// ReadSummary reads a summary written as json.
+func ReadSummary(filename string) (*Summary, error) {
+	s := new(Summary)
+	if err := readJSON(filename, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Compare finds the issues that are new, fixed, or still there in the
+// summary after compared with the one before.
+func Compare(before, after *Summary) *Comparison {
+	c := &Comparison{Checks: make(map[string][2]int), Packages: make(map[string][2]*PackageSummary)}
+	for path, p := range before.Packages {
+		c.Packages[path] = [2]*PackageSummary{p, after.Packages[path]}
+	}
+	for path, p := range after.Packages {
+		c.Packages[path] = [2]*PackageSummary{before.Packages[path], p}
+	}
+	remaining := make(map[BaselineIssue][]*Offender)
+	for _, section := range before.Sections() {
+		for _, o := range section.Offenders {
+			remaining[baselineIssue(o)] = append(remaining[baselineIssue(o)], o)
+		}
+		c.Checks[section.Check] = [2]int{len(section.Offenders), 0}
+	}
+	for _, section := range after.Sections() {
+		c.match(section.Offenders, remaining)
+		c.Checks[section.Check] = [2]int{c.Checks[section.Check][0], len(section.Offenders)}
+	}
+	for _, section := range before.Sections() {
+		c.unmatched(section.Offenders, remaining)
+	}
+	return c
+}
+
+// match pairs the offenders of the summary after with the remaining ones
+// from before, or adds them to the new ones.
+func (c *Comparison) match(offenders []*Offender, remaining map[BaselineIssue][]*Offender) {
+	for _, o := range offenders {
+		key := baselineIssue(o)
+		if matches := remaining[key]; len(matches) > 0 {
+			c.Unchanged = append(c.Unchanged, &Change{matches[0], o})
+			remaining[key] = matches[1:]
+		} else {
+			c.New = append(c.New, o)
+		}
+	}
+}
+
+// unmatched adds the offenders from before left over by match to the fixed
+// ones, in their order.
+func (c *Comparison) unmatched(offenders []*Offender, remaining map[BaselineIssue][]*Offender) {
+	for _, o := range offenders {
+		key := baselineIssue(o)
+		if matches := remaining[key]; len(matches) > 0 && matches[0] == o {
+			c.Fixed = append(c.Fixed, o)
+			remaining[key] = matches[1:]
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package lint
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// statementsOf returns a function with n statements.
+func statementsOf(name string, n int) string {
+	return "func " + name + "(n int) {\n" + strings.Repeat("\tn++\n", n) + "}\n\n"
+}
+
+func TestCompare(t *testing.T) {
+	opts := DefaultOptions()
+	opts.StatementThreshold = 3
+	filename := writeSource(t, "package a\n\n"+statementsOf("f", 4)+statementsOf("g", 5)+statementsOf("k", 2))
+	before := run(t, filename, opts)
+	src := "package a\n\n" + statementsOf("f", 6) + statementsOf("h", 4) + statementsOf("k", 2)
+	if err := os.WriteFile(filename, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	after := run(t, filename, opts)
+
+	c := Compare(before, after)
+	if len(c.New) != 1 || c.New[0].Function != "h" {
+		t.Errorf("new issues %v, want h", c.New)
+	}
+	if len(c.Fixed) != 1 || c.Fixed[0].Function != "g" {
+		t.Errorf("fixed issues %v, want g", c.Fixed)
+	}
+	if len(c.Unchanged) != 1 || c.Unchanged[0].New.Function != "f" || c.Unchanged[0].Delta() != 2 {
+		t.Errorf("unchanged issues %v, want f, grown by 2", c.Unchanged)
+	}
+	if got := c.Checks[CheckStatementCount]; got != [2]int{2, 2} {
+		t.Errorf("statement-count issues %v, want [2 2]", got)
+	}
+	for path, p := range c.Packages {
+		if p[0] == nil || p[1] == nil || p[0].Functions != 3 || p[1].Functions != 3 {
+			t.Errorf("package %s: %+v, %+v, want 3 functions before and after", path, p[0], p[1])
+		}
+	}
+}
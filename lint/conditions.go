@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// conditionCount holds the boolean operators and the comparisons of a
+// condition.
+type conditionCount struct {
+	operators, comparisons int
+}
+
+// countCondition counts the &&, || and ! operators and the comparisons
+// of a condition, leaving out function literals.
+func countCondition(cond ast.Expr) conditionCount {
+	var c conditionCount
+	ast.Inspect(cond, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			switch n.Op {
+			case token.LAND, token.LOR:
+				c.operators++
+			case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+				c.comparisons++
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.NOT {
+				c.operators++
+			}
+		}
+		return true
+	})
+	return c
+}
+
+// checkConditions looks for if and for conditions with too many boolean
+// operators or comparisons, reporting the count over the threshold.
+func (p *Parser) checkConditions(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		var cond ast.Expr
+		switch y := node.(type) {
+		case *ast.IfStmt:
+			cond = y.Cond
+		case *ast.ForStmt:
+			cond = y.Cond
+		}
+		if cond == nil {
+			return true
+		}
+		c := countCondition(cond)
+		if c.operators > p.opts.ConditionThreshold {
+			p.reportCondition(x, cond, "operators", c.operators)
+		} else if c.comparisons > p.opts.ConditionThreshold {
+			p.reportCondition(x, cond, "comparisons", c.comparisons)
+		}
+		return true
+	})
+}
+
+func (p *Parser) reportCondition(x *ast.FuncDecl, cond ast.Expr, metric string, count int) {
+	o := p.offender(funcName(x), count, cond)
+	o.Metric = metric
+	p.report(CheckCondition, o, p.summary.addCondition)
+}
@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// checkContext looks for functions taking a context.Context other than as
+// their first param, and, with type information, for params of struct
+// types holding a context.Context.
+func (p *Parser) checkContext(x *ast.FuncDecl) {
+	index := 0
+	for _, f := range x.Type.Params.List {
+		n := len(f.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			index++
+			if index > 1 && p.isContext(f.Type) {
+				p.report(CheckContext, p.offender(funcName(x), index, f), p.summary.addContext)
+			}
+		}
+		if p.holdsContext(f.Type) {
+			p.report(CheckContext, p.offender(funcName(x), 0, f), p.summary.addContext)
+		}
+	}
+}
+
+// isContext reports whether typ is context.Context.  Without type
+// information, it has to be spelled that way.
+func (p *Parser) isContext(typ ast.Expr) bool {
+	if p.info != nil {
+		if t := p.info.TypeOf(typ); t != nil {
+			return isContextType(t)
+		}
+	}
+	sel, ok := typ.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "context" && sel.Sel.Name == "Context"
+}
+
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == "context" && obj.Name() == "Context"
+}
+
+// holdsContext reports whether typ is a struct, or a pointer to one, with
+// a context.Context field.  It needs type information.
+func (p *Parser) holdsContext(typ ast.Expr) bool {
+	if p.info == nil {
+		return false
+	}
+	t := p.info.TypeOf(typ)
+	if t == nil {
+		return false
+	}
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	s, ok := t.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		if isContextType(s.Field(i).Type()) {
+			return true
+		}
+	}
+	return false
+}
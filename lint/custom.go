@@ -0,0 +1,95 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"sync"
+)
+
+// Check is a custom check, run by splint alongside the built-in ones.
+// Run is called with every function declaration of a file, then with the
+// file itself, and returns the offenders found in that node.  Offenders
+// are made with NewOffender; splint fills in their file, function and
+// package.
+type Check interface {
+	// Name identifies the check in directives, on the command line and
+	// in the output.  It must not be the name of a built-in check.
+	Name() string
+	// Doc describes what the check looks for, in a few words.
+	Doc() string
+	Run(node ast.Node) []*Offender
+}
+
+var (
+	customMu     sync.RWMutex
+	customChecks = make(map[string]Check)
+)
+
+// Register makes a custom check part of every run.  It is meant to be
+// called from the init function of the package defining the check, like
+// a plugin loaded with -plugin.  A check registered twice, or under the
+// name of a built-in check, is left out with an error.
+func Register(c Check) error {
+	customMu.Lock()
+	defer customMu.Unlock()
+	name := c.Name()
+	if isBuiltin(name) {
+		return fmt.Errorf("lint: check %s is a built-in check", name)
+	}
+	if _, ok := customChecks[name]; ok {
+		return fmt.Errorf("lint: check %s registered twice", name)
+	}
+	customChecks[name] = c
+	return nil
+}
+
+func isBuiltin(name string) bool {
+	for _, section := range new(Summary).builtinSections() {
+		if section.Check == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isCustom(name string) bool {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	_, ok := customChecks[name]
+	return ok
+}
+
+// CustomChecks returns the registered custom checks, sorted by name.
+func CustomChecks() []Check {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	checks := make([]Check, 0, len(customChecks))
+	for _, c := range customChecks {
+		checks = append(checks, c)
+	}
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name() < checks[j].Name() })
+	return checks
+}
+
+// NewOffender makes an offender for a custom check, at pos, with an
+// optional count, and described by message.
+func NewOffender(pos token.Pos, count int, message string) *Offender {
+	return &Offender{Pos: pos, Count: count, message: message}
+}
+
+// runCustom runs the custom checks on a function declaration or a file.
+func (p *Parser) runCustom(node ast.Node) {
+	for _, c := range CustomChecks() {
+		for _, found := range c.Run(node) {
+			function := ""
+			if p.fn != nil {
+				function = funcName(p.fn)
+			}
+			o := p.offenderAt(function, found.Count, found.Pos, found.Pos)
+			o.message = found.message
+			p.report(c.Name(), o, p.summary.addCustom)
+		}
+	}
+}
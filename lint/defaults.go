@@ -0,0 +1,37 @@
+package lint
+
+import "go/ast"
+
+// hasDefault reports whether the body of a switch has a default clause.
+func hasDefault(body *ast.BlockStmt) bool {
+	for _, stmt := range body.List {
+		if clause, ok := stmt.(*ast.CaseClause); ok && clause.List == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSwitchDefaults looks for switch and type switch statements with
+// more than opts.SwitchDefaultThreshold cases but no default clause, which
+// silently do nothing for the values the cases don't expect.
+func (p *Parser) checkSwitchDefaults(x *ast.FuncDecl) {
+	if !p.opts.SwitchDefaults {
+		return
+	}
+	inspectFunc(x, func(node ast.Node) bool {
+		var body *ast.BlockStmt
+		switch y := node.(type) {
+		case *ast.SwitchStmt:
+			body = y.Body
+		case *ast.TypeSwitchStmt:
+			body = y.Body
+		default:
+			return true
+		}
+		if n := len(body.List); n > p.opts.SwitchDefaultThreshold && !hasDefault(body) {
+			p.report(CheckSwitchDefault, p.offender(funcName(x), n, node), p.summary.addSwitchDefault)
+		}
+		return true
+	})
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LineRange is a range of lines in a file, First to Last inclusive.
+type LineRange struct {
+	First, Last int
+}
+
+// Changes maps absolute file names to the lines changed in them.  When set
+// in Options, only the offenders in changed functions are reported.
+type Changes map[string][]LineRange
+
+// ParseDiff reads the changed lines out of a unified diff, like the output
+// of "git diff".  The file names in the diff are taken relative to root,
+// less the b/ prefix git gives them.  The lines of each hunk are counted
+// off its header, so that an added line starting with "++ " is not taken
+// for the header of the next file.
+func ParseDiff(r io.Reader, root string) (Changes, error) {
+	changes := make(Changes)
+	var file string
+	var left hunk // the lines of the current hunk still to read
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case left.oldLines > 0 || left.newLines > 0:
+			left.count(line)
+		case strings.HasPrefix(line, "+++ "):
+			file = diffFilename(strings.TrimPrefix(line, "+++ "), root)
+		case strings.HasPrefix(line, "@@ "):
+			h, err := parseHunk(line)
+			if err != nil {
+				return nil, err
+			}
+			if file != "" {
+				changes[file] = append(changes[file], h.changed)
+			}
+			left = h
+		}
+	}
+	return changes, scanner.Err()
+}
+
+func diffFilename(name, root string) string {
+	if i := strings.IndexByte(name, '\t'); i >= 0 {
+		name = name[:i]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	name = strings.TrimPrefix(name, "b/")
+	return canonical(filepath.Join(root, filepath.FromSlash(name)))
+}
+
+// canonical returns the absolute path of a file with symlinks resolved,
+// so that diff and offender file names can be compared.
+func canonical(filename string) string {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return filename
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		return real
+	}
+	return abs
+}
+
+// hunk is what a hunk header like "@@ -12,3 +12,5 @@" tells: how many
+// old lines it removes and new lines it adds, and the new lines it touches.
+type hunk struct {
+	oldLines, newLines int
+	changed            LineRange
+}
+
+// count counts a line of the hunk off the lines left to read.
+func (h *hunk) count(line string) {
+	switch {
+	case strings.HasPrefix(line, "-"):
+		h.oldLines--
+	case strings.HasPrefix(line, "+"):
+		h.newLines--
+	case strings.HasPrefix(line, `\`): // "\ No newline at end of file"
+	default:
+		h.oldLines--
+		h.newLines--
+	}
+}
+
+// parseHunk reads a hunk header.  A hunk that only deletes lines touches
+// the line the deletion follows.
+func parseHunk(header string) (hunk, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[3] != "@@" {
+		return hunk{}, fmt.Errorf("bad hunk header %q", header)
+	}
+	_, oldLines, errOld := parseHunkRange(fields[1], "-")
+	first, newLines, errNew := parseHunkRange(fields[2], "+")
+	if errOld != nil || errNew != nil {
+		return hunk{}, fmt.Errorf("bad hunk header %q", header)
+	}
+	h := hunk{oldLines, newLines, LineRange{first, first + newLines - 1}}
+	if newLines == 0 {
+		h.changed.Last = first
+	}
+	return h, nil
+}
+
+// parseHunkRange reads a range of a hunk header like "+12,5", where the
+// number of lines defaults to 1.
+func parseHunkRange(field, sign string) (start, n int, err error) {
+	field, ok := strings.CutPrefix(field, sign)
+	if !ok {
+		return 0, 0, fmt.Errorf("no %s in %q", sign, field)
+	}
+	first, count, found := strings.Cut(field, ",")
+	if start, err = strconv.Atoi(first); err != nil {
+		return 0, 0, err
+	}
+	n = 1
+	if found {
+		n, err = strconv.Atoi(count)
+	}
+	return start, n, err
+}
+
+// touches checks if any of the lines of the function holding o changed.
+func (c Changes) touches(o *Offender) bool {
+	for _, lr := range c[canonical(o.Filename)] {
+		if lr.First <= o.Span.Last && lr.Last >= o.Span.First {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package lint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseHunk(t *testing.T) {
+	tests := []struct {
+		header string
+		want   hunk
+		err    bool
+	}{
+		{"@@ -12,3 +12,5 @@", hunk{3, 5, LineRange{12, 16}}, false},
+		{"@@ -12,3 +12,5 @@ func f() {", hunk{3, 5, LineRange{12, 16}}, false},
+		{"@@ -7 +7 @@", hunk{1, 1, LineRange{7, 7}}, false},
+		{"@@ -4,2 +3,0 @@", hunk{2, 0, LineRange{3, 3}}, false},
+		{"@@ -0,0 +1,4 @@", hunk{0, 4, LineRange{1, 4}}, false},
+		{"@@ -1,2 @@", hunk{}, true},
+		{"@@ -1,2 +x,3 @@", hunk{}, true},
+		{"@@ -1,2 +3,y @@", hunk{}, true},
+		{"@@ 1,2 +3,4 @@", hunk{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseHunk(tt.header)
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("parseHunk(%q) = %v, %v, want %v, error %v", tt.header, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParseDiff(t *testing.T) {
+	tests := []struct {
+		name, diff string
+		want       Changes
+	}{
+		{
+			"two files",
+			"diff --git a/a.go b/a.go\n--- a/a.go\n+++ b/a.go\n@@ -3 +3,2 @@\n-x\n+y\n+z\n" +
+				"@@ -10,2 +11,0 @@\n-p\n-q\n" +
+				"diff --git a/b/c.go b/b/c.go\n--- a/b/c.go\n+++ b/b/c.go\n@@ -1 +1 @@\n-a\n+b\n",
+			Changes{"/src/a.go": {{3, 4}, {11, 11}}, "/src/b/c.go": {{1, 1}}},
+		},
+		{
+			"added lines like headers",
+			"--- a/a.go\n+++ b/a.go\n@@ -1,2 +1,4 @@\n---- x\n-+++ y\n+++ b/c.go\n+@@ -5 +5 @@\n+--- a/c.go\n+y\n",
+			Changes{"/src/a.go": {{1, 4}}},
+		},
+		{
+			"context lines",
+			"--- a/a.go\n+++ b/a.go\n@@ -1,3 +1,3 @@\n a\n-b\n+c\n d\n@@ -9 +9 @@\n-e\n+f\n",
+			Changes{"/src/a.go": {{1, 3}, {9, 9}}},
+		},
+		{
+			"no newline at end of file",
+			"--- a/a.go\n+++ b/a.go\n@@ -1 +1 @@\n-a\n\\ No newline at end of file\n+b\n\\ No newline at end of file\n",
+			Changes{"/src/a.go": {{1, 1}}},
+		},
+		{
+			"deleted file",
+			"--- a/a.go\n+++ /dev/null\n@@ -1,2 +0,0 @@\n-a\n-+++ b/c.go\n--- a/d.go\n+++ b/d.go\n@@ -2 +2 @@\n-a\n+b\n",
+			Changes{"/src/d.go": {{2, 2}}},
+		},
+		{
+			"no prefix",
+			"--- a.go\t2024-01-01\n+++ a.go\t2024-01-02\n@@ -1 +1 @@\n-a\n+b\n",
+			Changes{"/src/a.go": {{1, 1}}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseDiff(strings.NewReader(tt.diff), "/src")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if _, err := ParseDiff(strings.NewReader("+++ b/a.go\n@@ -1 +x @@\n"), "/src"); err == nil {
+		t.Error("bad hunk header: no error")
+	}
+}
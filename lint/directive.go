@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"strings"
+)
+
+const (
+	ignoreDirective    = "//splint:ignore"
+	thresholdDirective = "//splint:threshold"
+)
+
+// ignore is a //splint:ignore directive, covering the source between from
+// and to.  An empty list of checks covers every check.
+type ignore struct {
+	checks   []string
+	reason   string
+	from, to token.Pos
+}
+
+func (ig *ignore) covers(check string, pos token.Pos) bool {
+	if pos < ig.from || pos > ig.to {
+		return false
+	}
+	if len(ig.checks) == 0 {
+		return true
+	}
+	for _, c := range ig.checks {
+		if c == check || hasID(check, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIgnore parses the text of a comment like
+//
+//	//splint:ignore statement-count,if-chain reason for ignoring
+//
+// and reports whether it was an ignore directive at all.
+func parseIgnore(text string) (checks []string, reason string, ok bool) {
+	if !strings.HasPrefix(text, ignoreDirective) {
+		return nil, "", false
+	}
+	rest := text[len(ignoreDirective):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return nil, "", false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return nil, "", true
+	}
+	reason = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), fields[0]))
+	return strings.Split(fields[0], ","), reason, true
+}
+
+// statementsByLine maps each line to the outermost declaration or statement
+// starting on it, or to the whole file for the line of the package clause.
+func statementsByLine(fileset *token.FileSet, tree *ast.File) map[int]ast.Node {
+	lines := make(map[int]ast.Node)
+	ast.Inspect(tree, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.File, ast.Stmt, ast.Decl:
+			line := fileset.Position(n.Pos()).Line
+			if _, ok := lines[line]; !ok {
+				lines[line] = n
+			}
+		}
+		return true
+	})
+	return lines
+}
+
+// findIgnores collects the ignore directives of a file.  A directive applies
+// to the function or statement on the line right after its comment, or to
+// the one it trails on the same line.  A directive right before the package
+// clause applies to the whole file.
+func findIgnores(fileset *token.FileSet, tree *ast.File) []*ignore {
+	var ignores []*ignore
+	var lines map[int]ast.Node
+	for _, cg := range tree.Comments {
+		for _, c := range cg.List {
+			checks, reason, ok := parseIgnore(c.Text)
+			if !ok {
+				continue
+			}
+			if lines == nil {
+				lines = statementsByLine(fileset, tree)
+			}
+			n, found := lines[fileset.Position(c.Pos()).Line]
+			if !found {
+				n, found = lines[fileset.Position(cg.End()).Line+1]
+			}
+			if found {
+				ignores = append(ignores, &ignore{checks, reason, n.Pos(), n.End()})
+			}
+		}
+	}
+	return ignores
+}
+
+// parseThresholds parses the text of a comment like
+//
+//	//splint:threshold statements=80,params=7 reason for the exception
+//
+// into the thresholds it sets, by check.  Checks are given by name, short
+// name, plural short name or ID, and entries that don't name a check with
+// a threshold are left out.
+func parseThresholds(text string) (map[string]int, string) {
+	rest, ok := strings.CutPrefix(text, thresholdDirective)
+	fields := strings.Fields(rest)
+	if !ok || len(fields) == 0 || (rest[0] != ' ' && rest[0] != '\t') {
+		return nil, ""
+	}
+	set := make(map[string]int)
+	for _, entry := range strings.Split(fields[0], ",") {
+		if check, n, err := parseThreshold(entry); err == nil {
+			set[check] = n
+		}
+	}
+	return set, strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), fields[0]))
+}
+
+// overrideThresholds applies the //splint:threshold directives in the doc
+// comment of a function to the options, and returns a function restoring
+// them.
+func (p *Parser) overrideThresholds(x *ast.FuncDecl) func() {
+	saved := p.opts
+	if x.Doc == nil {
+		return func() {}
+	}
+	for _, c := range x.Doc.List {
+		set, reason := parseThresholds(c.Text)
+		for _, check := range Checks() {
+			if n, ok := set[check]; ok {
+				*thresholds[check](&p.opts) = n
+				p.summary.addOverride(&Override{p.position(x.Pos()), funcName(x), check, n, reason})
+			}
+		}
+	}
+	return func() { p.opts = saved }
+}
@@ -0,0 +1,131 @@
+package lint
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestParseIgnore(t *testing.T) {
+	tests := []struct {
+		text   string
+		checks []string
+		reason string
+		ok     bool
+	}{
+		{"//splint:ignore", nil, "", true},
+		{"//splint:ignore statement-count", []string{"statement-count"}, "", true},
+		{"//splint:ignore statement-count,if-chain generated code", []string{"statement-count", "if-chain"}, "generated code", true},
+		{"//splint:ignore\tSPL001  kept  as is ", []string{"SPL001"}, "kept  as is", true},
+		{"//splint:ignored statement-count", nil, "", false},
+		{"// splint:ignore statement-count", nil, "", false},
+		{"// just a comment", nil, "", false},
+	}
+	for _, tt := range tests {
+		checks, reason, ok := parseIgnore(tt.text)
+		if !slices.Equal(checks, tt.checks) || reason != tt.reason || ok != tt.ok {
+			t.Errorf("parseIgnore(%q) = %q, %q, %v, want %q, %q, %v", tt.text, checks, reason, ok, tt.checks, tt.reason, tt.ok)
+		}
+	}
+}
+
+func TestParseThresholds(t *testing.T) {
+	tests := []struct {
+		text   string
+		set    map[string]int
+		reason string
+	}{
+		{"//splint:threshold statements=80", map[string]int{CheckStatementCount: 80}, ""},
+		{"//splint:threshold statements=80,params=7 table of cases", map[string]int{CheckStatementCount: 80, CheckParamCount: 7}, "table of cases"},
+		{"//splint:threshold statement-count=40", map[string]int{CheckStatementCount: 40}, ""},
+		{"//splint:threshold bogus=3,statements=many", map[string]int{}, ""},
+		{"//splint:threshold", nil, ""},
+		{"//splint:thresholds statements=80", nil, ""},
+		{"// statements=80", nil, ""},
+	}
+	for _, tt := range tests {
+		set, reason := parseThresholds(tt.text)
+		if !maps.Equal(set, tt.set) || reason != tt.reason {
+			t.Errorf("parseThresholds(%q) = %v, %q, want %v, %q", tt.text, set, reason, tt.set, tt.reason)
+		}
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	tests := []struct {
+		name       string
+		src        string
+		found      int
+		suppressed int
+		overrides  int
+	}{
+		{"none", `package a
+
+func f() {
+	a := 1
+	_ = a
+}
+`, 1, 0, 0},
+		{"ignore before", `package a
+
+//splint:ignore statement-count two is plenty
+func f() {
+	a := 1
+	_ = a
+}
+`, 0, 1, 0},
+		{"ignore other check", `package a
+
+//splint:ignore param-count
+func f() {
+	a := 1
+	_ = a
+}
+`, 1, 0, 0},
+		{"ignore whole file", `//splint:ignore
+package a
+
+func f() {
+	a := 1
+	_ = a
+}
+`, 0, 1, 0},
+		{"threshold", `package a
+
+// f is long.
+//
+//splint:threshold statements=5
+func f() {
+	a := 1
+	_ = a
+}
+`, 0, 0, 1},
+		{"threshold too low", `package a
+
+//splint:threshold statements=1
+func f() {
+	a := 1
+	_ = a
+}
+`, 1, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.StatementThreshold = 1
+			found := 0
+			summary := &Summary{Warn: func(o *Offender) {
+				if o.Check == CheckStatementCount {
+					found++
+				}
+			}}
+			if err := NewParser("a.go", summary, opts).ParseSource([]byte(tt.src)); err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.found || len(summary.Suppressed) != tt.suppressed || len(summary.Overrides) != tt.overrides {
+				t.Errorf("found %d, suppressed %d, overrides %d, want %d, %d, %d",
+					found, len(summary.Suppressed), len(summary.Overrides), tt.found, tt.suppressed, tt.overrides)
+			}
+		})
+	}
+}
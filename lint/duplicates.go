@@ -0,0 +1,294 @@
+package lint
+
+import (
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"hash/fnv"
+	"io"
+	"sort"
+)
+
+// funcShape is a function long enough to be compared with the others of
+// the run.  Like methods, duplicates can be spread over any files, so they
+// are only looked for once all the files are merged.  Statements holds the
+// sorted shape hashes of its statements, to compare it with functions of
+// a different shape.
+type funcShape struct {
+	Offender   *Offender
+	Statements []string
+	lateIgnore
+}
+
+// writeShape writes the shape of a node to h, with its identifiers and the
+// values of its literals left out.
+func writeShape(h io.Writer, root ast.Node) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		if node == nil {
+			fmt.Fprint(h, ")")
+			return false
+		}
+		fmt.Fprintf(h, "(%T", node)
+		switch n := node.(type) {
+		case *ast.BasicLit:
+			fmt.Fprint(h, n.Kind)
+		case *ast.BinaryExpr:
+			fmt.Fprint(h, n.Op)
+		case *ast.UnaryExpr:
+			fmt.Fprint(h, n.Op)
+		case *ast.AssignStmt:
+			fmt.Fprint(h, n.Tok)
+		case *ast.IncDecStmt:
+			fmt.Fprint(h, n.Tok)
+		case *ast.BranchStmt:
+			fmt.Fprint(h, n.Tok)
+		}
+		return true
+	})
+}
+
+// shapeHash hashes a node with its identifiers and the values of its
+// literals left out, so that copies with renamed variables or different
+// constants hash the same.
+func shapeHash(node ast.Node) string {
+	h := fnv.New64a()
+	writeShape(h, node)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// statementShapes returns the sorted shape hashes of the statements of a
+// body, at any depth.  A copy with a statement changed still shares the
+// hashes of the statements around it.
+func statementShapes(body *ast.BlockStmt) []string {
+	var hashes []string
+	ast.Inspect(body, func(node ast.Node) bool {
+		if stmt, ok := node.(ast.Stmt); ok && stmt != body {
+			hashes = append(hashes, shapeHash(stmt))
+		}
+		return true
+	})
+	sort.Strings(hashes)
+	return hashes
+}
+
+// similarity returns the percentage of statement shapes two functions
+// share, counting each shape as many times as they both have it.
+func similarity(a, b []string) int {
+	if len(a)+len(b) == 0 {
+		return 100
+	}
+	common := 0
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			common++
+			i++
+			j++
+		case a[i] < b[j]:
+			i++
+		default:
+			j++
+		}
+	}
+	return common * 200 / (len(a) + len(b))
+}
+
+// recordShape records the shape of a function with at least
+// opts.DuplicateThreshold statements.
+func (p *Parser) recordShape(x *ast.FuncDecl) {
+	if x.Body == nil || !p.opts.enabled(CheckDuplicate) || statementCount(x) < p.opts.DuplicateThreshold {
+		return
+	}
+	o := p.offender(funcName(x), 0, x)
+	o.Check = CheckDuplicate
+	o.ID = CheckID(CheckDuplicate)
+	o.Severity = p.opts.severity(CheckDuplicate)
+	shape := &funcShape{Offender: o, Statements: statementShapes(x.Body)}
+	shape.Reason, shape.Ignored = p.suppressed(CheckDuplicate, x.Pos())
+	if p.summary.shapes == nil {
+		p.summary.shapes = make(map[string][]*funcShape)
+	}
+	hash := shapeHash(x.Body)
+	p.summary.shapes[hash] = append(p.summary.shapes[hash], shape)
+}
+
+// mergeShapes adds the functions found in other files.
+func (s *Summary) mergeShapes(shapes map[string][]*funcShape) {
+	if len(shapes) > 0 && s.shapes == nil {
+		s.shapes = make(map[string][]*funcShape)
+	}
+	for hash, list := range shapes {
+		s.shapes[hash] = append(s.shapes[hash], list...)
+	}
+}
+
+func shapeLess(a, b *funcShape) bool {
+	if a.Offender.Filename != b.Offender.Filename {
+		return a.Offender.Filename < b.Offender.Filename
+	}
+	return a.Offender.Position.Offset < b.Offender.Position.Offset
+}
+
+// shapeGroups joins the functions into groups, each function with the
+// others it was found similar to.
+type shapeGroups struct {
+	shapes []*funcShape
+	parent []int
+}
+
+func (g *shapeGroups) root(i int) int {
+	for g.parent[i] != i {
+		g.parent[i] = g.parent[g.parent[i]]
+		i = g.parent[i]
+	}
+	return g.parent[i]
+}
+
+func (g *shapeGroups) join(i, j int) {
+	g.parent[g.root(j)] = g.root(i)
+}
+
+// joinSimilar joins the functions sharing at least percent of their
+// statement shapes.  Only the first function of each group of exact copies
+// is compared, and only with the functions sharing one of the rarest
+// statements in its prefix: two functions can only be that similar if
+// their prefixes meet.
+func (g *shapeGroups) joinSimilar(percent int, firsts []int) {
+	prefixes := g.prefixes(percent, firsts)
+	index := make(map[string][]int)
+	for k, i := range firsts {
+		for _, j := range candidates(index, prefixes[k]) {
+			if g.root(i) != g.root(j) && similarity(g.shapes[i].Statements, g.shapes[j].Statements) >= percent {
+				g.join(j, i)
+			}
+		}
+		for _, token := range prefixes[k] {
+			index[token] = append(index[token], i)
+		}
+	}
+}
+
+// prefixes returns the rarest statement tokens of the given functions, as
+// many as similarPrefix asks for.
+func (g *shapeGroups) prefixes(percent int, firsts []int) [][]string {
+	tokens := make([][]string, len(firsts))
+	frequency := make(map[string]int)
+	for k, i := range firsts {
+		tokens[k] = statementTokens(g.shapes[i].Statements)
+		for _, token := range tokens[k] {
+			frequency[token]++
+		}
+	}
+	for k, list := range tokens {
+		sort.Slice(list, func(x, y int) bool {
+			fx, fy := frequency[list[x]], frequency[list[y]]
+			return fx < fy || fx == fy && list[x] < list[y]
+		})
+		tokens[k] = list[:similarPrefix(len(list), percent)]
+	}
+	return tokens
+}
+
+// candidates returns the functions indexed under any of the tokens, once
+// each.
+func candidates(index map[string][]int, tokens []string) []int {
+	var found []int
+	seen := make(map[int]bool)
+	for _, token := range tokens {
+		for _, j := range index[token] {
+			if !seen[j] {
+				seen[j] = true
+				found = append(found, j)
+			}
+		}
+	}
+	return found
+}
+
+// statementTokens tells apart the repeats of a statement shape in sorted
+// hashes, like "h#0" and "h#1", so that sets of tokens share as many of
+// them as the hashes do.
+func statementTokens(hashes []string) []string {
+	tokens := make([]string, len(hashes))
+	for i, hash := range hashes {
+		n := 0
+		for i-n > 0 && hashes[i-n-1] == hash {
+			n++
+		}
+		tokens[i] = fmt.Sprintf("%s#%d", hash, n)
+	}
+	return tokens
+}
+
+// similarPrefix returns how many of the n statement tokens of a function
+// are enough to find every function sharing percent of their statements
+// with it.  Such a function shares at least percent*n/(200-percent) of the
+// tokens, so it has one among any n minus that plus one of them.
+func similarPrefix(n, percent int) int {
+	shared := (percent*n + 199 - percent) / (200 - percent)
+	return min(max(n-shared+1, 0), n)
+}
+
+// duplicateGroups returns the groups of functions with the same shape, or
+// sharing at least percent of their statement shapes when it is below 100,
+// ordered by position.
+func (s *Summary) duplicateGroups(percent int) [][]*funcShape {
+	g := new(shapeGroups)
+	var firsts []int
+	for _, list := range s.shapes {
+		first := len(g.shapes)
+		firsts = append(firsts, first)
+		for _, shape := range list {
+			g.parent = append(g.parent, len(g.shapes))
+			g.shapes = append(g.shapes, shape)
+			g.join(first, len(g.shapes)-1)
+		}
+	}
+	if percent > 0 && percent < 100 {
+		g.joinSimilar(percent, firsts)
+	}
+	members := make(map[int][]*funcShape)
+	for i, shape := range g.shapes {
+		members[g.root(i)] = append(members[g.root(i)], shape)
+	}
+	var groups [][]*funcShape
+	for _, list := range members {
+		if len(list) < 2 {
+			continue
+		}
+		sort.Slice(list, func(i, j int) bool { return shapeLess(list[i], list[j]) })
+		groups = append(groups, list)
+	}
+	sort.Slice(groups, func(i, j int) bool { return shapeLess(groups[i][0], groups[j][0]) })
+	return groups
+}
+
+// CheckDuplicates reports the functions that have the same structure as
+// another function merged into the summary, once their identifiers and
+// literals are left out, or that share opts.DuplicateSimilarity percent
+// of their statements with it.  Like CheckMethods, it needs to be called
+// once every file is merged; Run and RunPackages do.
+func (s *Summary) CheckDuplicates(opts Options) {
+	for _, group := range s.duplicateGroups(opts.DuplicateSimilarity) {
+		for _, shape := range group {
+			o := *shape.Offender
+			o.Count = len(group)
+			for _, other := range group {
+				if other != shape {
+					o.Duplicates = append(o.Duplicates, describeDuplicate(shape, other))
+				}
+			}
+			s.mergeLate(&o, shape.lateIgnore, opts)
+		}
+	}
+}
+
+// describeDuplicate names the other function of a group, with how similar
+// it is to shape unless they are the same.
+func describeDuplicate(shape, other *funcShape) string {
+	desc := fmt.Sprintf("%s %s", other.Offender.Position, other.Offender.Function)
+	if n := similarity(shape.Statements, other.Statements); n < 100 {
+		desc += fmt.Sprintf(" (%d%% similar)", n)
+	}
+	return desc
+}
@@ -0,0 +1,74 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// longFunc returns a function of ten statements, the last of which is
+// given.
+func longFunc(name, last string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %s(n int) int {\n", name)
+	for i := range 9 {
+		fmt.Fprintf(&b, "\tn += %d\n", i)
+		if i%3 == 0 {
+			b.WriteString("\tn *= 2\n")
+		}
+	}
+	fmt.Fprintf(&b, "\t%s\n\treturn n\n}\n\n", last)
+	return b.String()
+}
+
+func TestDuplicates(t *testing.T) {
+	tests := []struct {
+		name       string
+		funcs      []string
+		similarity int
+		want       []int
+	}{
+		{"exact copies", []string{longFunc("f", "n--"), longFunc("g", "n--")}, 100, []int{2, 2}},
+		{"other constants", []string{longFunc("f", "n -= 1"), longFunc("g", "n -= 2")}, 100, []int{2, 2}},
+		{"one statement changed", []string{longFunc("f", "n--"), longFunc("g", "println(n)")}, 100, nil},
+		{"near duplicates", []string{longFunc("f", "n--"), longFunc("g", "println(n)")}, 90, []int{2, 2}},
+		{"near and exact duplicates", []string{longFunc("f", "n--"), longFunc("g", "println(n)"), longFunc("h", "n--")}, 90, []int{3, 3, 3}},
+		{"different", []string{longFunc("f", "n--"), "func g() {\n\tfor {\n\t}\n}\n"}, 90, nil},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.DuplicateSimilarity = tt.similarity
+		summary := run(t, writeSource(t, "package a\n\n"+strings.Join(tt.funcs, "")), opts)
+		var got []int
+		for _, o := range summary.Duplicates {
+			got = append(got, o.Count)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: duplicate counts %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestStatementTokens(t *testing.T) {
+	got := statementTokens([]string{"a", "b", "b", "b", "c"})
+	want := []string{"a#0", "b#0", "b#1", "b#2", "c#0"}
+	if !slices.Equal(got, want) {
+		t.Errorf("statementTokens = %v, want %v", got, want)
+	}
+}
+
+func TestSimilarPrefix(t *testing.T) {
+	tests := []struct{ n, percent, want int }{
+		{10, 90, 2},
+		{20, 90, 4},
+		{10, 50, 7},
+		{10, 100, 1},
+		{0, 90, 0},
+	}
+	for _, tt := range tests {
+		if got := similarPrefix(tt.n, tt.percent); got != tt.want {
+			t.Errorf("similarPrefix(%d, %d) = %d, want %d", tt.n, tt.percent, got, tt.want)
+		}
+	}
+}
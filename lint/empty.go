@@ -0,0 +1,31 @@
+package lint
+
+import "go/ast"
+
+// checkEmptyBodies looks for for loops with an empty body, busy looping
+// unless their condition or post statement does the work, and for switch
+// and select statements without a case, like select {}, which blocks
+// forever.
+func (p *Parser) checkEmptyBodies(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		switch y := node.(type) {
+		case *ast.ForStmt:
+			if len(y.Body.List) == 0 {
+				p.report(CheckEmptyFor, p.offender(funcName(x), 0, y), p.summary.addEmptyFor)
+			}
+		case *ast.SwitchStmt:
+			if len(y.Body.List) == 0 {
+				p.report(CheckEmptySwitch, p.offender(funcName(x), 0, y), p.summary.addEmptySwitch)
+			}
+		case *ast.TypeSwitchStmt:
+			if len(y.Body.List) == 0 {
+				p.report(CheckEmptySwitch, p.offender(funcName(x), 0, y), p.summary.addEmptySwitch)
+			}
+		case *ast.SelectStmt:
+			if len(y.Body.List) == 0 {
+				p.report(CheckEmptySelect, p.offender(funcName(x), 0, y), p.summary.addEmptySelect)
+			}
+		}
+		return true
+	})
+}
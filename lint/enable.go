@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LookupChecks resolves a list of check names or IDs, failing on the first
+// unknown one.
+func LookupChecks(names []string) ([]string, error) {
+	var checks []string
+	for _, name := range names {
+		check, ok := LookupCheck(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown check %q", name)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
+
+// Disable turns off the checks named, by name or ID.
+func (opts *Options) Disable(names ...string) error {
+	checks, err := LookupChecks(names)
+	if err != nil {
+		return err
+	}
+	for _, check := range checks {
+		if opts.Disabled == nil {
+			opts.Disabled = make(map[string]bool)
+		}
+		opts.Disabled[check] = true
+	}
+	return nil
+}
+
+// Enable turns off every check but the ones named, by name or ID.  Checks
+// that are off unless given a threshold still need one.
+func (opts *Options) Enable(names ...string) error {
+	checks, err := LookupChecks(names)
+	if err != nil {
+		return err
+	}
+	enabled := make(map[string]bool)
+	for _, check := range checks {
+		enabled[check] = true
+	}
+	for _, check := range Checks() {
+		if !enabled[check] {
+			_ = opts.Disable(check)
+		}
+	}
+	return nil
+}
+
+// enabled reports whether a check was left on by Enable and Disable.
+func (opts *Options) enabled(check string) bool {
+	return !opts.Disabled[check]
+}
@@ -0,0 +1,145 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// neverFail holds the functions whose error results are discarded by
+// convention, as printing to stdout can't fail in a way callers would
+// handle.
+var neverFail = map[string]bool{
+	"fmt.Print":   true,
+	"fmt.Printf":  true,
+	"fmt.Println": true,
+}
+
+// fprintFuncs print to the writer they take first, and only fail when it
+// does.
+var fprintFuncs = map[string]bool{
+	"fmt.Fprint":   true,
+	"fmt.Fprintf":  true,
+	"fmt.Fprintln": true,
+}
+
+// discardedErrors counts the error results a function drops, assigning
+// them to _ or calling the function as a statement, leaving out function
+// literals.  It needs type information.
+func (p *Parser) discardedErrors(x *ast.FuncDecl) int {
+	total := 0
+	inspectFunc(x, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.ExprStmt:
+			if call, ok := n.X.(*ast.CallExpr); ok && p.returnsError(call) {
+				total++
+			}
+		case *ast.AssignStmt:
+			total += p.blankErrors(n)
+		}
+		return true
+	})
+	return total
+}
+
+// returnsError reports whether a call has an error among its results.
+func (p *Parser) returnsError(call *ast.CallExpr) bool {
+	if p.neverFails(call) {
+		return false
+	}
+	switch t := p.info.TypeOf(call).(type) {
+	case *types.Tuple:
+		for i := 0; i < t.Len(); i++ {
+			if isErrorType(t.At(i).Type()) {
+				return true
+			}
+		}
+	case types.Type:
+		return isErrorType(t)
+	}
+	return false
+}
+
+// blankErrors counts the error values an assignment gives to _.
+func (p *Parser) blankErrors(n *ast.AssignStmt) int {
+	total := 0
+	for i, lhs := range n.Lhs {
+		if id, ok := lhs.(*ast.Ident); !ok || id.Name != "_" {
+			continue
+		}
+		var t types.Type
+		if len(n.Lhs) == len(n.Rhs) {
+			t = p.info.TypeOf(n.Rhs[i])
+		} else if tuple, ok := p.info.TypeOf(n.Rhs[0]).(*types.Tuple); ok && i < tuple.Len() {
+			t = tuple.At(i).Type()
+		}
+		if t != nil && isErrorType(t) {
+			total++
+		}
+	}
+	return total
+}
+
+// neverFails reports whether a call is to one of the neverFail functions,
+// or writes to a writer that never fails.
+func (p *Parser) neverFails(call *ast.CallExpr) bool {
+	var id *ast.Ident
+	var recv ast.Expr
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fun
+	case *ast.SelectorExpr:
+		id, recv = fun.Sel, fun.X
+	default:
+		return false
+	}
+	fn, ok := p.info.Uses[id].(*types.Func)
+	switch {
+	case !ok:
+		return false
+	case fprintFuncs[fn.FullName()]:
+		return len(call.Args) > 0 && neverFailingWriter(p.info.TypeOf(call.Args[0]))
+	case strings.HasPrefix(fn.Name(), "Write") && recv != nil:
+		return neverFailingWriter(p.info.TypeOf(recv))
+	}
+	return neverFail[fn.FullName()]
+}
+
+// neverFailingWriter reports whether t is a writer whose methods return an
+// error only to implement io.Writer and the like: bytes.Buffer,
+// strings.Builder, and hashes, going by their Sum and BlockSize methods.
+func neverFailingWriter(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	elem := t
+	if ptr, ok := t.(*types.Pointer); ok {
+		elem = ptr.Elem()
+	}
+	switch types.TypeString(elem, nil) {
+	case "bytes.Buffer", "strings.Builder":
+		return true
+	}
+	for _, name := range []string{"Sum", "BlockSize"} {
+		if obj, _, _ := types.LookupFieldOrMethod(t, true, nil, name); obj == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isErrorType(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+func (p *Parser) checkDiscardedErrors(x *ast.FuncDecl) {
+	if p.info == nil {
+		return
+	}
+	numDiscarded := p.discardedErrors(x)
+	if numDiscarded <= p.opts.DiscardedErrorThreshold {
+		return
+	}
+
+	p.report(CheckDiscardedError, p.offender(funcName(x), numDiscarded, x), p.summary.addDiscardedErrors)
+}
@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"go/ast"
+	"strings"
+)
+
+// exitFuncs are the functions ending the program, by package name.
+var exitFuncs = map[string][]string{
+	"os":  {"Exit"},
+	"log": {"Fatal", "Fatalf", "Fatalln"},
+}
+
+// isExitCall reports whether a call is to os.Exit or log.Fatal, going by
+// how it is spelled.
+func isExitCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	for _, name := range exitFuncs[pkg.Name] {
+		if sel.Sel.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// topLevel returns the name of the function declaring x, which is x itself
+// unless x is a function literal, and whether it is a plain function
+// rather than a method.
+func topLevel(x *ast.FuncDecl) (string, bool) {
+	name, _, _ := strings.Cut(x.Name.Name, ".")
+	return name, x.Recv == nil
+}
+
+// inMainOrInit reports whether a function is main or init, or a function
+// literal inside them, which have no caller to return an error to.
+func inMainOrInit(x *ast.FuncDecl) bool {
+	name, ok := topLevel(x)
+	return ok && (name == "main" || name == "init")
+}
+
+// mayExit reports whether a function is main, init or TestMain, or a
+// function literal inside them, which are expected to end the program.
+func mayExit(x *ast.FuncDecl) bool {
+	name, ok := topLevel(x)
+	return inMainOrInit(x) || ok && name == "TestMain"
+}
+
+// checkExits looks for calls ending the program outside of main, which
+// skip deferred calls and leave callers no way to recover, or to be
+// tested.
+func (p *Parser) checkExits(x *ast.FuncDecl) {
+	if mayExit(x) {
+		return
+	}
+	inspectFunc(x, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok && isExitCall(call) {
+			p.report(CheckExit, p.offender(funcName(x), 0, call), p.summary.addExit)
+		}
+		return true
+	})
+}
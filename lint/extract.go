@@ -0,0 +1,242 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+)
+
+// minExtract is the number of statements below which a run of statements
+// is not worth a function of its own.
+const minExtract = 5
+
+// maxOutputs is the number of variables an extracted function may return.
+const maxOutputs = 2
+
+// Suggestion is a run of top-level statements of a long function that
+// could be extracted into a function of its own.  Inputs are the
+// variables the statements use that are declared before them, and Outputs
+// the variables they set that are used after them.
+type Suggestion struct {
+	Lines      LineRange
+	Statements int
+	Inputs     []string
+	Outputs    []string
+}
+
+// String describes the suggestion, like "lines 12-34 use only x and err;
+// consider extracting them".
+func (s *Suggestion) String() string {
+	uses := "use no local variables"
+	if len(s.Inputs) > 0 {
+		uses = "use only " + joinNames(s.Inputs)
+	}
+	msg := fmt.Sprintf("lines %d-%d %s; consider extracting them", s.Lines.First, s.Lines.Last, uses)
+	if len(s.Outputs) > 0 {
+		msg += " into a function returning " + joinNames(s.Outputs)
+	}
+	return msg
+}
+
+// joinNames lists names like "a, b and c".
+func joinNames(names []string) string {
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// stmtVars holds the local variables a top-level statement declares, at
+// any depth, sets, including its top-level declarations, and uses.
+type stmtVars struct {
+	declared, set, used localNames
+	escapes             bool
+}
+
+// varsOf collects the variables of a top-level statement among locals,
+// and whether control can leave it other than by falling through, which
+// makes it impossible to extract.
+func varsOf(stmt ast.Stmt, locals map[string]bool) stmtVars {
+	v := stmtVars{declared: make(localNames), set: make(localNames), used: make(localNames)}
+	ast.Inspect(stmt, v.declared.visit)
+	ast.Inspect(stmt, v.visitor(locals))
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		v.set.add(s.Lhs...)
+	case *ast.DeclStmt:
+		ast.Inspect(s, v.set.visit)
+	}
+	v.escapes = escapes(stmt)
+	return v
+}
+
+// visitor returns the function adding the variables a node sets with =
+// or ++ and --, and the locals it uses, leaving out field and method
+// names.
+func (v stmtVars) visitor(locals map[string]bool) func(ast.Node) bool {
+	var visit func(ast.Node) bool
+	visit = func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.SelectorExpr:
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.AssignStmt:
+			if n.Tok != token.DEFINE {
+				v.set.add(n.Lhs...)
+			}
+		case *ast.IncDecStmt:
+			v.set.add(n.X)
+		case *ast.Ident:
+			if locals[n.Name] {
+				v.used.add(n)
+			}
+		}
+		return true
+	}
+	return visit
+}
+
+// escapes reports whether a statement returns, defers, or jumps with goto
+// or to a label, leaving out function literals.
+func escapes(stmt ast.Stmt) (found bool) {
+	ast.Inspect(stmt, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt, *ast.DeferStmt, *ast.LabeledStmt:
+			found = true
+		case *ast.BranchStmt:
+			found = found || n.Tok == token.GOTO || n.Label != nil
+		}
+		return !found
+	})
+	return found
+}
+
+// functionLocals returns the names of the params, results and local
+// variables of a function.
+func functionLocals(x *ast.FuncDecl) map[string]bool {
+	locals := make(localNames)
+	for _, list := range []*ast.FieldList{x.Recv, x.Type.Params, x.Type.Results} {
+		if list == nil {
+			continue
+		}
+		for _, f := range list.List {
+			for _, name := range f.Names {
+				locals.add(name)
+			}
+		}
+	}
+	ast.Inspect(x.Body, locals.visit)
+	return locals
+}
+
+// extractRange is the run of statements from first to last, inclusive,
+// of the top-level statements with the given variables and statement
+// counts.
+type extractRange struct {
+	vars        []stmtVars
+	counts      []int
+	first, last int
+}
+
+// inputs are the variables used in the run without being declared in it.
+func (r extractRange) inputs() []string {
+	declared := make(map[string]bool)
+	var names []string
+	for _, v := range r.vars[r.first : r.last+1] {
+		for name := range v.used {
+			if !declared[name] && !v.declared[name] && !contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		for name := range v.set {
+			declared[name] = declared[name] || v.declared[name]
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// outputs are the variables declared or set in the run and used after it.
+func (r extractRange) outputs() []string {
+	var names []string
+	for _, v := range r.vars[r.first : r.last+1] {
+		for name := range v.set {
+			if r.usedAfter(name) && !contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r extractRange) usedAfter(name string) bool {
+	for _, v := range r.vars[r.last+1:] {
+		if v.used[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// suggestExtract looks for the longest run of top-level statements of a
+// function that could become a function of its own, short enough to be
+// under the statement threshold and needing no more params and results
+// than their thresholds allow.  Both the run and what is left of the
+// function must be worth a function.  It returns nil if there is none.
+func (p *Parser) suggestExtract(x *ast.FuncDecl) *Suggestion {
+	if x.Body == nil {
+		return nil
+	}
+	locals := functionLocals(x)
+	stmts := x.Body.List
+	r := extractRange{vars: make([]stmtVars, len(stmts)), counts: make([]int, len(stmts))}
+	total := 0
+	for i, stmt := range stmts {
+		r.vars[i] = varsOf(stmt, locals)
+		r.counts[i] = statementCount(stmt)
+		total += r.counts[i]
+	}
+	var best *Suggestion
+	for r.first = range stmts {
+		if s := p.longestFrom(stmts, r, total, best); s != nil {
+			best = s
+		}
+	}
+	return best
+}
+
+// longestFrom returns the longest run of stmts starting at r.first that
+// can be extracted and is longer than best, or nil.
+func (p *Parser) longestFrom(stmts []ast.Stmt, r extractRange, total int, best *Suggestion) *Suggestion {
+	var found *Suggestion
+	n := 0
+	for r.last = r.first; r.last < len(r.vars) && !r.vars[r.last].escapes; r.last++ {
+		n += r.counts[r.last]
+		if n > p.opts.StatementThreshold || total-n < minExtract {
+			break
+		}
+		if n < minExtract || (best != nil && n <= best.Statements) {
+			continue
+		}
+		inputs, outputs := r.inputs(), r.outputs()
+		if len(inputs) <= p.opts.ParamThreshold && len(outputs) <= maxOutputs {
+			lines := LineRange{p.position(stmts[r.first].Pos()).Line, p.position(stmts[r.last].End()).Line}
+			found = &Suggestion{lines, n, inputs, outputs}
+		}
+	}
+	return found
+}
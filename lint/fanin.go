@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// complexFunc is a function over the cognitive complexity threshold, which
+// the fan-in check reports when it is called from too many places.
+type complexFunc struct {
+	Offender *Offender
+	lateIgnore
+}
+
+// recordCalls records, with type information, the functions and methods
+// x calls, one call site per call, and x itself when it is too complex.
+// Function literals record their own calls, as they are checked on their
+// own.
+func (p *Parser) recordCalls(x *ast.FuncDecl) {
+	if p.info == nil || !p.opts.enabled(CheckFanIn) {
+		return
+	}
+	if p.summary.callSites == nil {
+		p.summary.callSites = make(map[string]int)
+	}
+	inspectFunc(x, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if name := p.callee(call); name != "" {
+				p.summary.callSites[name]++
+			}
+		}
+		return true
+	})
+	p.recordComplex(x)
+}
+
+// recordComplex records x when it is over the cognitive complexity
+// threshold, for CheckFanIn to report if it is called too much.
+func (p *Parser) recordComplex(x *ast.FuncDecl) {
+	fn, ok := p.info.Defs[x.Name].(*types.Func)
+	if !ok {
+		return
+	}
+	if cognitiveComplexity(x) <= p.opts.CognitiveThreshold {
+		return
+	}
+	o := p.offender(funcName(x), 0, x)
+	o.Check = CheckFanIn
+	o.ID = CheckID(CheckFanIn)
+	o.Severity = p.opts.severity(CheckFanIn)
+	c := &complexFunc{Offender: o}
+	c.Reason, c.Ignored = p.suppressed(CheckFanIn, x.Pos())
+	if p.summary.complexFuncs == nil {
+		p.summary.complexFuncs = make(map[string]*complexFunc)
+	}
+	p.summary.complexFuncs[fn.FullName()] = c
+}
+
+// mergeCalls adds the call sites and complex functions found in other
+// files.
+func (s *Summary) mergeCalls(other *Summary) {
+	if len(other.callSites) > 0 && s.callSites == nil {
+		s.callSites = make(map[string]int)
+	}
+	for name, n := range other.callSites {
+		s.callSites[name] += n
+	}
+	if len(other.complexFuncs) > 0 && s.complexFuncs == nil {
+		s.complexFuncs = make(map[string]*complexFunc)
+	}
+	for name, c := range other.complexFuncs {
+		s.complexFuncs[name] = c
+	}
+}
+
+// CheckFanIn reports the functions over the cognitive complexity
+// threshold that are called from more places than opts.FanInThreshold,
+// most called first, as changing them affects the most code.  It needs
+// the call sites of every package, recorded with type information, so
+// RunPackages calls it once every package is merged.
+func (s *Summary) CheckFanIn(opts Options) {
+	type called struct {
+		c *complexFunc
+		n int
+	}
+	var list []called
+	for name, c := range s.complexFuncs {
+		if n := s.callSites[name]; n > opts.FanInThreshold {
+			list = append(list, called{c, n})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].n != list[j].n {
+			return list[i].n > list[j].n
+		}
+		return list[i].c.Offender.Position.String() < list[j].c.Offender.Position.String()
+	})
+	for _, l := range list {
+		o := *l.c.Offender
+		o.Count = l.n
+		o.Threshold = opts.threshold(&o)
+		s.mergeLate(&o, l.c.lateIgnore, opts)
+	}
+}
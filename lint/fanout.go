@@ -0,0 +1,66 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// builtins are the builtin functions, which are not counted as callees
+// without type information.
+var builtins = map[string]bool{
+	"append": true, "cap": true, "clear": true, "close": true, "complex": true,
+	"copy": true, "delete": true, "imag": true, "len": true, "make": true,
+	"max": true, "min": true, "new": true, "panic": true, "print": true,
+	"println": true, "real": true, "recover": true,
+}
+
+// callee names the function or method a call is to, like "fmt.Println"
+// or "p.report", or returns "" for calls to builtins.  With type
+// information, methods are named after their type rather than the
+// receiver, and conversions are left out.
+func (p *Parser) callee(call *ast.CallExpr) string {
+	fun := ast.Unparen(call.Fun)
+	if p.info != nil {
+		if tv, ok := p.info.Types[fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+			return ""
+		}
+		var id *ast.Ident
+		switch f := fun.(type) {
+		case *ast.Ident:
+			id = f
+		case *ast.SelectorExpr:
+			id = f.Sel
+		}
+		if fn, ok := p.info.Uses[id].(*types.Func); ok {
+			return fn.FullName()
+		}
+	}
+	if id, ok := fun.(*ast.Ident); ok && builtins[id.Name] {
+		return ""
+	}
+	return types.ExprString(fun)
+}
+
+// fanOut counts the distinct functions and methods a function calls,
+// leaving out function literals.
+func (p *Parser) fanOut(x *ast.FuncDecl) int {
+	callees := make(map[string]bool)
+	inspectFunc(x, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			if name := p.callee(call); name != "" {
+				callees[name] = true
+			}
+		}
+		return true
+	})
+	return len(callees)
+}
+
+func (p *Parser) checkFanOut(x *ast.FuncDecl) {
+	numCallees := p.fanOut(x)
+	if numCallees <= p.opts.FanOutThreshold {
+		return
+	}
+
+	p.report(CheckFanOut, p.offender(funcName(x), numCallees, x), p.summary.addFanOut)
+}
@@ -0,0 +1,155 @@
+package lint
+
+import (
+	"bytes"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// examineFile runs the checks on the file as a whole.  Their offenders are
+// reported at the package clause, and span the whole file.
+func (p *Parser) examineFile(tree *ast.File) {
+	p.checkFileLength(tree)
+	p.checkFileFunctions(tree)
+	p.checkLineLength(tree)
+	p.checkImports(tree)
+	p.checkGlobals(tree)
+	p.checkMarkers(tree)
+	p.checkFileMaintainability(tree)
+}
+
+// fileOffender is like offender, for an issue with a whole file.
+func (p *Parser) fileOffender(count int, tree *ast.File) *Offender {
+	o := p.offenderAt("", count, tree.Package, tree.FileEnd)
+	o.Span = LineRange{1, p.fileset.File(tree.Package).LineCount()}
+	return o
+}
+
+func (p *Parser) checkFileLength(tree *ast.File) {
+	if p.opts.FileLineThreshold <= 0 {
+		return
+	}
+	numLines := p.fileset.File(tree.Package).LineCount()
+	if numLines <= p.opts.FileLineThreshold {
+		return
+	}
+
+	p.report(CheckFileLength, p.fileOffender(numLines, tree), p.summary.addLongFile)
+}
+
+// checkFileFunctions counts the functions and methods declared in a file.
+func (p *Parser) checkFileFunctions(tree *ast.File) {
+	if p.opts.FileFunctionThreshold <= 0 {
+		return
+	}
+	numFuncs := 0
+	for _, decl := range tree.Decls {
+		if _, ok := decl.(*ast.FuncDecl); ok {
+			numFuncs++
+		}
+	}
+	if numFuncs <= p.opts.FileFunctionThreshold {
+		return
+	}
+
+	p.report(CheckFileFunctions, p.fileOffender(numFuncs, tree), p.summary.addFileFunctions)
+}
+
+// source returns the contents of the file, reading it if the parser was
+// not given them.
+func (p *Parser) source() ([]byte, error) {
+	if p.src == nil {
+		src, err := p.opts.readFile(p.filename)
+		if err != nil {
+			return nil, err
+		}
+		p.src = src
+	}
+	return p.src, nil
+}
+
+// lineWidth returns the number of columns a line takes, with tabs taking
+// opts.TabWidth columns, or one if it isn't set.
+func (p *Parser) lineWidth(line []byte) int {
+	tabs := bytes.Count(line, []byte("\t"))
+	width := utf8.RuneCount(line) - tabs
+	if p.opts.TabWidth > 0 {
+		return width + tabs*p.opts.TabWidth
+	}
+	return width + tabs
+}
+
+// checkLineLength reports every line wider than opts.MaxLineLength.
+func (p *Parser) checkLineLength(tree *ast.File) {
+	if p.opts.MaxLineLength <= 0 {
+		return
+	}
+	src, err := p.source()
+	if err != nil {
+		return
+	}
+	file := p.fileset.File(tree.Package)
+	for i, line := range bytes.Split(src, []byte("\n")) {
+		line = bytes.TrimRight(line, "\r")
+		width := p.lineWidth(line)
+		if width <= p.opts.MaxLineLength || i >= file.LineCount() {
+			continue
+		}
+		start := file.LineStart(i + 1)
+		o := p.offenderAt("", width, start, start+token.Pos(len(line)))
+		o.Span = LineRange{i + 1, i + 1}
+		p.report(CheckLineLength, o, p.summary.addLongLine)
+	}
+}
+
+// isStdlib reports whether an import path is in the standard library,
+// whose paths have no dot in their first element.
+func isStdlib(path string) bool {
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".")
+}
+
+func (p *Parser) checkImports(tree *ast.File) {
+	numImports := len(tree.Imports)
+	if numImports <= p.opts.ImportThreshold {
+		return
+	}
+	counts := new(ImportCounts)
+	for _, spec := range tree.Imports {
+		if path, err := strconv.Unquote(spec.Path.Value); err == nil && isStdlib(path) {
+			counts.Stdlib++
+		} else {
+			counts.ThirdParty++
+		}
+	}
+	o := p.fileOffender(numImports, tree)
+	o.Imports = counts
+	p.report(CheckImports, o, p.summary.addImports)
+}
+
+// checkGlobals counts the package level variables of a file, other than
+// the blank ones, which hold no state.
+func (p *Parser) checkGlobals(tree *ast.File) {
+	numGlobals := 0
+	for _, decl := range tree.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				if name.Name != "_" {
+					numGlobals++
+				}
+			}
+		}
+	}
+	if numGlobals <= p.opts.GlobalThreshold {
+		return
+	}
+
+	p.report(CheckGlobals, p.fileOffender(numGlobals, tree), p.summary.addGlobals)
+}
@@ -0,0 +1,79 @@
+package lint
+
+import "fmt"
+
+// The methods adding the offenders of the checks on files, lines and
+// types, which adders holds along with the ones of the checks on functions.
+
+func (s *Summary) addMarkerFile(o *Offender) {
+	s.MarkerFiles = append(s.MarkerFiles, o)
+	s.NumAboveMarkerThreshold++
+	o.fileWarning("has too many TODO markers")
+	s.record(o)
+}
+
+func (s *Summary) addLongFile(o *Offender) {
+	s.LongFiles = append(s.LongFiles, o)
+	s.NumLongFiles++
+	o.fileWarning("too long")
+	s.record(o)
+}
+
+func (s *Summary) addFileFunctions(o *Offender) {
+	s.FileFunctions = append(s.FileFunctions, o)
+	s.NumAboveFileFunctionThreshold++
+	o.fileWarning("has too many functions")
+	s.record(o)
+}
+
+func (s *Summary) addStruct(o *Offender) {
+	s.Structs = append(s.Structs, o)
+	s.NumAboveStructFieldThreshold++
+	o.typeWarning("struct", "has too many fields")
+	s.record(o)
+}
+
+func (s *Summary) addInterface(o *Offender) {
+	s.Interfaces = append(s.Interfaces, o)
+	s.NumAboveInterfaceMethodThreshold++
+	o.typeWarning("interface", "has too many methods")
+	s.record(o)
+}
+
+func (s *Summary) addTypeMethods(o *Offender) {
+	s.TypeMethods = append(s.TypeMethods, o)
+	s.NumAboveMethodThreshold++
+	o.typeWarning("type", "has too many methods")
+	s.record(o)
+}
+
+func (s *Summary) addMixedReceivers(o *Offender) {
+	s.MixedReceivers = append(s.MixedReceivers, o)
+	s.NumMixedReceivers++
+	o.receiversWarning()
+	s.record(o)
+}
+
+func (s *Summary) addLongLine(o *Offender) {
+	s.LongLines = append(s.LongLines, o)
+	s.NumLongLines++
+	o.lineWarning("too long")
+	s.record(o)
+}
+
+func (s *Summary) addImports(o *Offender) {
+	s.Imports = append(s.Imports, o)
+	s.NumAboveImportThreshold++
+	o.fileWarning("has too many imports")
+	if o.Imports != nil {
+		o.message += fmt.Sprintf(" (%d stdlib, %d third-party)", o.Imports.Stdlib, o.Imports.ThirdParty)
+	}
+	s.record(o)
+}
+
+func (s *Summary) addGlobals(o *Offender) {
+	s.Globals = append(s.Globals, o)
+	s.NumAboveGlobalThreshold++
+	o.fileWarning("has too many package variables")
+	s.record(o)
+}
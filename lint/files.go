@@ -0,0 +1,41 @@
+package lint
+
+import "sort"
+
+// FileSummary holds the offenders of a file, in the order of their
+// positions, and counts them by check.
+type FileSummary struct {
+	Filename  string
+	Offenders []*Offender
+	Counts    map[string]int
+	Total     int
+}
+
+// NewFileSummaries groups the offenders of a summary by file, sorted by
+// file name, for consumers that report issues file by file.  Files
+// without offenders are left out.
+func NewFileSummaries(s *Summary) []*FileSummary {
+	byName := make(map[string]*FileSummary)
+	var files []*FileSummary
+	for _, section := range s.Sections() {
+		for _, o := range section.Offenders {
+			f, ok := byName[o.Filename]
+			if !ok {
+				f = &FileSummary{Filename: o.Filename, Counts: make(map[string]int)}
+				byName[o.Filename] = f
+				files = append(files, f)
+			}
+			f.Offenders = append(f.Offenders, o)
+			f.Counts[o.Check]++
+			f.Total++
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Filename < files[j].Filename })
+	for _, f := range files {
+		sort.SliceStable(f.Offenders, func(i, j int) bool {
+			a, b := f.Offenders[i].Position, f.Offenders[j].Position
+			return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+		})
+	}
+	return files
+}
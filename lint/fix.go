@@ -0,0 +1,190 @@
+package lint
+
+import (
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"sort"
+)
+
+// edit replaces the source between start and end, as offsets, with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// fixer collects the edits fixing the empty ifs of a file.
+type fixer struct {
+	fileset *token.FileSet
+	tree    *ast.File
+	src     []byte
+	edits   []edit
+	fixed   int
+
+	// reported holds the offsets of the empty ifs splint reports, the
+	// only ones fixed.
+	reported map[int]bool
+
+	// elseIfs are the ifs in the else branch of another, which can't be
+	// removed without leaving a dangling else.
+	elseIfs map[ast.Node]bool
+}
+
+// FixEmptyIfs rewrites the if statements with an empty body in src.  Ifs
+// without an else are removed, unless they have an init statement or a
+// condition that may have side effects, and ifs with an else get the
+// negated condition and the else branch as their body.  It returns the
+// formatted source and the number of ifs fixed.  Ifs holding comments are
+// left alone, and so are those Run would not report: in files it skips,
+// like generated files or those excluded by build constraints, in
+// functions left out by opts.IgnoreFuncs or opts.OnlyFuncs, silenced by
+// //splint:ignore directives, recorded in opts.Baseline or outside
+// opts.Changes.
+func FixEmptyIfs(filename string, src []byte, opts Options) ([]byte, int, error) {
+	reported, err := reportedEmptyIfs(filename, src, opts)
+	if err != nil || len(reported) == 0 {
+		return src, 0, err
+	}
+	fileset := token.NewFileSet()
+	tree, err := parser.ParseFile(fileset, filename, src, parser.ParseComments)
+	if err != nil {
+		return src, 0, err
+	}
+	f := &fixer{fileset: fileset, tree: tree, src: src, reported: reported, elseIfs: make(map[ast.Node]bool)}
+	ast.Inspect(tree, f.visit)
+	if f.fixed == 0 {
+		return src, 0, nil
+	}
+	sort.Slice(f.edits, func(i, j int) bool { return f.edits[i].start > f.edits[j].start })
+	out := append([]byte(nil), src...)
+	for _, e := range f.edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	formatted, err := format.Source(out)
+	if err != nil {
+		return src, 0, err
+	}
+	return formatted, f.fixed, nil
+}
+
+// reportedEmptyIfs returns the offsets of the empty ifs Run reports in a
+// file.
+func reportedEmptyIfs(filename string, src []byte, opts Options) (map[int]bool, error) {
+	reported := make(map[int]bool)
+	summary := &Summary{Warn: func(o *Offender) {
+		if o.Check != CheckEmptyIf || opts.Changes != nil && !opts.Changes.touches(o) {
+			return
+		}
+		if opts.Baseline == nil || !opts.Baseline.consume(o) {
+			reported[o.Position.Offset] = true
+		}
+	}}
+	err := NewParser(filename, summary, opts).ParseSource(src)
+	return reported, err
+}
+
+func (f *fixer) offset(pos token.Pos) int {
+	return f.fileset.Position(pos).Offset
+}
+
+func (f *fixer) text(n ast.Node) string {
+	return string(f.src[f.offset(n.Pos()):f.offset(n.End())])
+}
+
+// hasComments reports whether a comment lies within n.
+func (f *fixer) hasComments(n ast.Node) bool {
+	for _, cg := range f.tree.Comments {
+		if cg.Pos() >= n.Pos() && cg.End() <= n.End() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fixer) visit(node ast.Node) bool {
+	x, ok := node.(*ast.IfStmt)
+	if !ok {
+		return true
+	}
+	f.elseIfs[x.Else] = true
+	if !f.reported[f.offset(x.Pos())] || f.hasComments(x.Body) {
+		return true
+	}
+	switch {
+	case x.Else != nil:
+		f.invert(x)
+	case x.Init == nil && !sideEffects(x.Cond) && !f.elseIfs[x]:
+		f.remove(x)
+	}
+	return true
+}
+
+// invert turns "if cond {} else {...}" into "if !cond {...}".  An else if
+// becomes the only statement of the new body.
+func (f *fixer) invert(x *ast.IfStmt) {
+	cond := negate(x.Cond, f.text)
+	if _, ok := x.Else.(*ast.BlockStmt); ok {
+		f.edits = append(f.edits, edit{f.offset(x.Cond.Pos()), f.offset(x.Else.Pos()), cond + " "})
+	} else {
+		f.edits = append(f.edits,
+			edit{f.offset(x.Cond.Pos()), f.offset(x.Else.Pos()), cond + " {\n"},
+			edit{f.offset(x.Else.End()), f.offset(x.Else.End()), "\n}"})
+	}
+	f.fixed++
+}
+
+// remove deletes an if statement, with its line when it has one of its
+// own.
+func (f *fixer) remove(x *ast.IfStmt) {
+	start, end := f.offset(x.Pos()), f.offset(x.End())
+	for start > 0 && (f.src[start-1] == ' ' || f.src[start-1] == '\t') {
+		start--
+	}
+	for end < len(f.src) && (f.src[end] == ' ' || f.src[end] == '\t') {
+		end++
+	}
+	if (start == 0 || f.src[start-1] == '\n') && end < len(f.src) && f.src[end] == '\n' {
+		end++
+	} else {
+		start, end = f.offset(x.Pos()), f.offset(x.End())
+	}
+	f.edits = append(f.edits, edit{start, end, ""})
+	f.fixed++
+}
+
+// negate returns the source of the negation of a condition, given the
+// source of its parts.
+func negate(cond ast.Expr, text func(ast.Node) string) string {
+	switch e := cond.(type) {
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			return text(e.X)
+		}
+	case *ast.BinaryExpr:
+		if e.Op == token.EQL {
+			return text(e.X) + " != " + text(e.Y)
+		}
+		if e.Op == token.NEQ {
+			return text(e.X) + " == " + text(e.Y)
+		}
+	case *ast.Ident, *ast.CallExpr, *ast.SelectorExpr, *ast.ParenExpr, *ast.IndexExpr:
+		return "!" + text(cond)
+	}
+	return "!(" + text(cond) + ")"
+}
+
+// sideEffects reports whether evaluating an expression may have side
+// effects, that is whether it calls a function or receives from a channel.
+func sideEffects(e ast.Expr) (found bool) {
+	ast.Inspect(e, func(node ast.Node) bool {
+		switch x := node.(type) {
+		case *ast.CallExpr:
+			found = true
+		case *ast.UnaryExpr:
+			found = found || x.Op == token.ARROW
+		}
+		return !found
+	})
+	return found
+}
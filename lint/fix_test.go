@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"regexp"
+	"testing"
+)
+
+const emptyIfs = `package a
+
+func f(x int) int {
+	if x > 0 {
+	}
+	return x
+}
+`
+
+func TestFixEmptyIfs(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		src      string
+		opts     func(*Options)
+		want     string
+		fixed    int
+	}{
+		{"removed", "a.go", emptyIfs, nil, "package a\n\nfunc f(x int) int {\n\treturn x\n}\n", 1},
+		{"inverted", "a.go", "package a\n\nfunc f(x int) {\n\tif x > 0 {\n\t} else {\n\t\tx++\n\t}\n}\n", nil,
+			"package a\n\nfunc f(x int) {\n\tif !(x > 0) {\n\t\tx++\n\t}\n}\n", 1},
+		{"inverted equality", "a.go", "package a\n\nfunc f(x int) {\n\tif x == 0 {\n\t} else {\n\t\tx++\n\t}\n}\n", nil,
+			"package a\n\nfunc f(x int) {\n\tif x != 0 {\n\t\tx++\n\t}\n}\n", 1},
+		{"else if", "a.go", "package a\n\nfunc f(x int) {\n\tif x == 0 {\n\t} else if x > 1 {\n\t\tx++\n\t}\n}\n", nil,
+			"package a\n\nfunc f(x int) {\n\tif x != 0 {\n\t\tif x > 1 {\n\t\t\tx++\n\t\t}\n\t}\n}\n", 1},
+		{"side effects", "a.go", "package a\n\nfunc f(g func() bool) {\n\tif g() {\n\t}\n}\n", nil, "", 0},
+		{"init statement", "a.go", "package a\n\nfunc f(x int) {\n\tif y := x; y > 0 {\n\t}\n}\n", nil, "", 0},
+		{"comment", "a.go", "package a\n\nfunc f(x int) {\n\tif x > 0 {\n\t\t// nothing yet\n\t}\n}\n", nil, "", 0},
+		{"ignored", "a.go", "package a\n\n//splint:ignore empty-if\nfunc f(x int) {\n\tif x > 0 {\n\t}\n}\n", nil, "", 0},
+		{"disabled", "a.go", emptyIfs, func(opts *Options) { opts.Disabled = map[string]bool{CheckEmptyIf: true} }, "", 0},
+		{"generated", "a.go", "// Code generated by hand. DO NOT EDIT.\n\n" + emptyIfs, nil, "", 0},
+		{"generated included", "a.go", "// Code generated by hand. DO NOT EDIT.\n\n" + emptyIfs,
+			func(opts *Options) { opts.IncludeGenerated = true },
+			"// Code generated by hand. DO NOT EDIT.\n\npackage a\n\nfunc f(x int) int {\n\treturn x\n}\n", 1},
+		{"other platform", "a_linux_arm.go", emptyIfs, func(opts *Options) { opts.GOOS, opts.GOARCH = "linux", "amd64" }, "", 0},
+		{"cgo", "a.go", "package a\n\nimport \"C\"\n\nfunc f(x int) {\n\tif x > 0 {\n\t}\n}\n", func(opts *Options) { opts.SkipCgo = true }, "", 0},
+		{"ignored function", "a.go", emptyIfs, func(opts *Options) { opts.IgnoreFuncs = regexp.MustCompile("^f$") }, "", 0},
+		{"other functions only", "a.go", emptyIfs, func(opts *Options) { opts.OnlyFuncs = regexp.MustCompile("^g$") }, "", 0},
+		{"baseline", "a.go", emptyIfs, func(opts *Options) {
+			opts.Baseline = &Baseline{Issues: []BaselineIssue{{Check: CheckEmptyIf, Filename: "a.go", Function: "f"}}}
+		}, "", 0},
+		{"unchanged", "a.go", emptyIfs, func(opts *Options) { opts.Changes = Changes{canonical("a.go"): {{First: 9, Last: 9}}} }, "", 0},
+		{"changed", "a.go", emptyIfs, func(opts *Options) { opts.Changes = Changes{canonical("a.go"): {{First: 4, Last: 4}}} },
+			"package a\n\nfunc f(x int) int {\n\treturn x\n}\n", 1},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		if tt.opts != nil {
+			tt.opts(&opts)
+		}
+		want := tt.want
+		if tt.fixed == 0 {
+			want = tt.src
+		}
+		got, fixed, err := FixEmptyIfs(tt.filename, []byte(tt.src), opts)
+		if err != nil || string(got) != want || fixed != tt.fixed {
+			t.Errorf("%s: FixEmptyIfs = %q, %d, %v, want %q, %d", tt.name, got, fixed, err, want, tt.fixed)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package lint
+
+import (
+	"go/ast"
+	"regexp"
+)
+
+// funcName returns the name a function is reported under, qualified by
+// its receiver type for methods, like Server.Start for both (*Server).Start
+// and (Server).Start.
+func funcName(x *ast.FuncDecl) string {
+	if t := receiverType(x); t != "" {
+		return t + "." + x.Name.Name
+	}
+	return x.Name.Name
+}
+
+// wantFunc reports whether the checks run on a function, going by
+// OnlyFuncs and IgnoreFuncs, which are matched against its name and, for
+// methods, its name qualified by its receiver type, like T.String.
+func (opts *Options) wantFunc(x *ast.FuncDecl) bool {
+	names := []string{x.Name.Name}
+	if name := funcName(x); name != x.Name.Name {
+		names = append(names, name)
+	}
+	matches := func(re *regexp.Regexp) bool {
+		for _, name := range names {
+			if re.MatchString(name) {
+				return true
+			}
+		}
+		return false
+	}
+	if opts.OnlyFuncs != nil && !matches(opts.OnlyFuncs) {
+		return false
+	}
+	return opts.IgnoreFuncs == nil || !matches(opts.IgnoreFuncs)
+}
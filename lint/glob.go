@@ -0,0 +1,33 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CompileGlob turns a glob pattern for slash-separated paths into a
+// regular expression for Options.Exclude.  "*" and "?" match within a
+// single path element, "**" matches across elements, and a leading "**/"
+// also matches no directory at all, so "**/gen.go" matches "gen.go".
+func CompileGlob(pattern string) (*regexp.Regexp, error) {
+	var re strings.Builder
+	re.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			re.WriteString("[^/]*")
+		case pattern[i] == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	re.WriteString("$")
+	return regexp.Compile(re.String())
+}
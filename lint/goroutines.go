@@ -0,0 +1,58 @@
+package lint
+
+import "go/ast"
+
+// goCount counts the go statements of a function, leaving out function
+// literals.
+func goCount(x *ast.FuncDecl) int {
+	return countNodes(x, func(node ast.Node) bool {
+		_, ok := node.(*ast.GoStmt)
+		return ok
+	})
+}
+
+func (p *Parser) checkGoroutines(x *ast.FuncDecl) {
+	p.checkGoInLoops(x)
+	numGo := goCount(x)
+	if numGo <= p.opts.GoroutineThreshold {
+		return
+	}
+
+	p.report(CheckGoroutines, p.offender(funcName(x), numGo, x), p.summary.addGoroutines)
+}
+
+// goInLoop returns the go statements of a loop body, leaving out the ones
+// of nested loops and function literals.
+func goInLoop(body *ast.BlockStmt) []*ast.GoStmt {
+	var found []*ast.GoStmt
+	ast.Inspect(body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.FuncLit, *ast.ForStmt, *ast.RangeStmt:
+			return false
+		case *ast.GoStmt:
+			found = append(found, n)
+		}
+		return true
+	})
+	return found
+}
+
+// checkGoInLoops looks for go statements inside for loops, which start an
+// unbounded number of goroutines unless something else limits them.
+func (p *Parser) checkGoInLoops(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		var body *ast.BlockStmt
+		switch n := node.(type) {
+		case *ast.ForStmt:
+			body = n.Body
+		case *ast.RangeStmt:
+			body = n.Body
+		default:
+			return true
+		}
+		for _, stmt := range goInLoop(body) {
+			p.report(CheckGoInLoop, p.offender(funcName(x), 0, stmt), p.summary.addGoInLoop)
+		}
+		return true
+	})
+}
@@ -0,0 +1,44 @@
+package lint
+
+import "go/ast"
+
+// minGuardStatements is the number of statements an if body must list for
+// the guard clause check to suggest inverting it, as short ifs read fine.
+const minGuardStatements = 5
+
+// wrappedPercent returns the percentage of the statements of a function
+// that a single top-level if without an else spans, counting the if, the
+// largest one if there are several, and that if.
+func wrappedPercent(x *ast.FuncDecl) (int, *ast.IfStmt) {
+	if x.Body == nil {
+		return 0, nil
+	}
+	total := statementCount(x.Body) - 1
+	var best *ast.IfStmt
+	percent := 0
+	for _, stmt := range x.Body.List {
+		ifst, ok := stmt.(*ast.IfStmt)
+		if !ok || ifst.Else != nil {
+			continue
+		}
+		if len(ifst.Body.List) < minGuardStatements {
+			continue
+		}
+		if n := statementCount(ifst) * 100 / total; n > percent {
+			best, percent = ifst, n
+		}
+	}
+	return percent, best
+}
+
+// checkGuardClause looks for functions whose body is mostly one if, which
+// reads better as a guard clause returning early when the condition
+// doesn't hold, with the rest of the function unindented.
+func (p *Parser) checkGuardClause(x *ast.FuncDecl) {
+	percent, ifst := wrappedPercent(x)
+	if ifst == nil || percent <= p.opts.GuardThreshold {
+		return
+	}
+
+	p.report(CheckGuardClause, p.offender(funcName(x), percent, ifst), p.summary.addGuardClause)
+}
@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// Halstead holds the Halstead metrics of a function, computed from its
+// operators, like + or a function call, and its operands, the names and
+// literals they apply to.
+type Halstead struct {
+	Volume     float64
+	Difficulty float64
+	Effort     float64
+}
+
+// halsteadCounts counts each distinct operator and operand.
+type halsteadCounts struct {
+	operators map[string]int
+	operands  map[string]int
+}
+
+func sumCounts(counts map[string]int) int {
+	n := 0
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
+
+// tokenOperator returns the operator token of expressions and statements
+// that have one.
+func tokenOperator(node ast.Node) (token.Token, bool) {
+	switch n := node.(type) {
+	case *ast.BinaryExpr:
+		return n.Op, true
+	case *ast.UnaryExpr:
+		return n.Op, true
+	case *ast.AssignStmt:
+		return n.Tok, true
+	case *ast.IncDecStmt:
+		return n.Tok, true
+	case *ast.BranchStmt:
+		return n.Tok, true
+	case *ast.SendStmt:
+		return token.ARROW, true
+	}
+	return token.ILLEGAL, false
+}
+
+// keywordOperator returns the keyword of the statements that have one,
+// other than the branch statements.
+func keywordOperator(node ast.Node) (op string) {
+	switch node.(type) {
+	case *ast.IfStmt:
+		op = "if"
+	case *ast.ForStmt:
+		op = "for"
+	case *ast.RangeStmt:
+		op = "range"
+	case *ast.ReturnStmt:
+		op = "return"
+	case *ast.GoStmt:
+		op = "go"
+	case *ast.DeferStmt:
+		op = "defer"
+	case *ast.SwitchStmt, *ast.TypeSwitchStmt:
+		op = "switch"
+	case *ast.SelectStmt:
+		op = "select"
+	case *ast.CaseClause, *ast.CommClause:
+		op = "case"
+	}
+	return op
+}
+
+// syntaxOperator returns the punctuation making up an expression, like
+// the parentheses of a call.
+func syntaxOperator(node ast.Node) (op string) {
+	switch node.(type) {
+	case *ast.CallExpr:
+		op = "()"
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		op = "[]"
+	case *ast.SliceExpr:
+		op = "[:]"
+	case *ast.SelectorExpr:
+		op = "."
+	case *ast.StarExpr:
+		op = "*"
+	case *ast.TypeAssertExpr:
+		op = ".()"
+	case *ast.CompositeLit:
+		op = "{}"
+	case *ast.KeyValueExpr:
+		op = ":"
+	case *ast.FuncLit:
+		op = "func"
+	}
+	return op
+}
+
+func (h *halsteadCounts) visit(node ast.Node) bool {
+	if tok, ok := tokenOperator(node); ok {
+		h.operators[tok.String()]++
+	} else if op := keywordOperator(node); op != "" {
+		h.operators[op]++
+	} else if op := syntaxOperator(node); op != "" {
+		h.operators[op]++
+	}
+	switch n := node.(type) {
+	case *ast.Ident:
+		h.operands[n.Name]++
+	case *ast.BasicLit:
+		h.operands[n.Value]++
+	}
+	return true
+}
+
+// halstead computes the Halstead metrics of the body of a function.
+func halstead(x *ast.FuncDecl) Halstead {
+	h := &halsteadCounts{make(map[string]int), make(map[string]int)}
+	if x.Body != nil {
+		ast.Inspect(x.Body, h.visit)
+	}
+	n1, n2 := len(h.operators), len(h.operands)
+	if n2 == 0 {
+		return Halstead{}
+	}
+	length := sumCounts(h.operators) + sumCounts(h.operands)
+	volume := float64(length) * math.Log2(float64(n1+n2))
+	difficulty := float64(n1) / 2 * float64(sumCounts(h.operands)) / float64(n2)
+	return Halstead{volume, difficulty, volume * difficulty}
+}
+
+// checkHalstead reports the functions with a Halstead volume, difficulty
+// or effort above its threshold.  The thresholds are off when zero.
+func (p *Parser) checkHalstead(x *ast.FuncDecl) {
+	if p.opts.HalsteadVolume <= 0 && p.opts.HalsteadDifficulty <= 0 && p.opts.HalsteadEffort <= 0 {
+		return
+	}
+	h := halstead(x)
+	limits := []struct {
+		metric     string
+		value, max float64
+	}{
+		{"volume", h.Volume, p.opts.HalsteadVolume},
+		{"difficulty", h.Difficulty, p.opts.HalsteadDifficulty},
+		{"effort", h.Effort, p.opts.HalsteadEffort},
+	}
+	for _, l := range limits {
+		if l.max > 0 && l.value > l.max {
+			o := p.offender(funcName(x), int(math.Round(l.value)), x)
+			o.Metric = l.metric
+			p.report(CheckHalstead, o, p.summary.addHalstead)
+		}
+	}
+}
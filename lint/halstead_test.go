@@ -0,0 +1,63 @@
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"testing"
+)
+
+// parseFunc parses the first function of a file holding src.
+func parseFunc(t *testing.T, src string) *ast.FuncDecl {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "a.go", "package a\n\n"+src+"\n", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f.Decls[0].(*ast.FuncDecl)
+}
+
+func TestHalstead(t *testing.T) {
+	tests := []struct {
+		src  string
+		want Halstead
+	}{
+		{"func f() {}", Halstead{}},
+		// return and +, on a and b.
+		{"func f(a, b int) int {\n\treturn a + b\n}", Halstead{8, 1, 8}},
+		// :=, * and =, on x and a twice and _ once.
+		{"func f(a int) {\n\tx := a * a\n\t_ = x\n}", Halstead{8 * math.Log2(6), 2.5, 20 * math.Log2(6)}},
+		// the call, on f and 1.
+		{"func f() {\n\tf(1)\n}", Halstead{3 * math.Log2(3), 0.5, 1.5 * math.Log2(3)}},
+	}
+	for _, tt := range tests {
+		got := halstead(parseFunc(t, tt.src))
+		if !near(got.Volume, tt.want.Volume) || !near(got.Difficulty, tt.want.Difficulty) || !near(got.Effort, tt.want.Effort) {
+			t.Errorf("halstead(%q) = %+v, want %+v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestCheckHalstead(t *testing.T) {
+	opts := DefaultOptions()
+	opts.HalsteadVolume = 9
+	opts.HalsteadEffort = 100
+	var found []*Offender
+	summary := &Summary{Warn: func(o *Offender) {
+		if o.Check == CheckHalstead {
+			found = append(found, o)
+		}
+	}}
+	src := "package a\n\nfunc f(a, b int) int {\n\treturn a + b\n}\n\nfunc g(a, b, c int) int {\n\treturn a + b*c\n}\n"
+	if err := NewParser("a.go", summary, opts).ParseSource([]byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 1 || found[0].Function != "g" || found[0].Metric != "volume" || found[0].Count != 16 {
+		t.Errorf("halstead issues %v, want the volume of g, 16", found)
+	}
+}
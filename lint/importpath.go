@@ -0,0 +1,67 @@
+package lint
+
+import (
+	"go/build"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+var importPaths = struct {
+	sync.Mutex
+	byDir map[string]string
+}{byDir: make(map[string]string)}
+
+// importPath guesses the import path of the package in a directory, from
+// the go.mod file of its module or from GOPATH.  Directories outside of
+// both are keyed by their slash-separated path.
+func importPath(dir string) string {
+	importPaths.Lock()
+	defer importPaths.Unlock()
+	if p, ok := importPaths.byDir[dir]; ok {
+		return p
+	}
+	p := findImportPath(dir)
+	importPaths.byDir[dir] = p
+	return p
+}
+
+func findImportPath(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(dir)
+	}
+	for root := abs; ; root = filepath.Dir(root) {
+		if mod := modulePath(filepath.Join(root, "go.mod")); mod != "" {
+			rel, _ := filepath.Rel(root, abs)
+			return path.Join(mod, filepath.ToSlash(rel))
+		}
+		if filepath.Dir(root) == root {
+			break
+		}
+	}
+	for _, gopath := range filepath.SplitList(build.Default.GOPATH) {
+		src := filepath.Join(gopath, "src") + string(filepath.Separator)
+		if strings.HasPrefix(abs, src) {
+			return filepath.ToSlash(abs[len(src):])
+		}
+	}
+	return filepath.ToSlash(dir)
+}
+
+// modulePath returns the module path declared in a go.mod file, or "" if
+// there is none.
+func modulePath(gomod string) string {
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "module"); ok {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,479 @@
+// Package lint implements the checks performed by splint.  It finds any
+// functions that are too long or have too many parameters or results.
+package lint
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"regexp"
+)
+
+// Options holds the thresholds used by the checks, and controls which
+// files Run looks at.
+//
+//splint:ignore struct-fields a threshold for each check
+type Options struct {
+	StatementThreshold       int
+	InitThreshold            int
+	ParamThreshold           int
+	VariadicThreshold        int
+	ResultThreshold          int
+	ReturnThreshold          int
+	LocalThreshold           int
+	IfChainThreshold         int
+	IfBodyThreshold          int
+	SwitchCaseThreshold      int
+	TypeSwitchCaseThreshold  int
+	CaseBodyThreshold        int
+	LabelThreshold           int
+	ConditionThreshold       int
+	GuardThreshold           int
+	ChainThreshold           int
+	GoroutineThreshold       int
+	FanOutThreshold          int
+	FanInThreshold           int
+	ClosureDepthThreshold    int
+	DiscardedErrorThreshold  int
+	CognitiveThreshold       int
+	DuplicateThreshold       int
+	DuplicateSimilarity      int
+	StructFieldThreshold     int
+	InterfaceMethodThreshold int
+	MethodThreshold          int
+	IgnoreTestFiles          bool
+
+	// TestThresholds replaces the thresholds of the checks it names, by
+	// check, in test files, like to allow longer test functions while
+	// still catching the longest ones.
+	TestThresholds map[string]int
+
+	// LineThreshold turns on the line count check, which measures the
+	// length of functions in source lines, from the func keyword to the
+	// closing brace.  Zero leaves it off.
+	LineThreshold int
+
+	// FileLineThreshold is the number of lines above which files are
+	// too long.  Zero turns the check off.
+	FileLineThreshold int
+
+	// FileFunctionThreshold is the number of functions and methods
+	// above which a file should be split.  Zero turns the check off.
+	FileFunctionThreshold int
+	ImportThreshold       int
+	GlobalThreshold       int
+
+	// MarkerThreshold is the number of TODO, FIXME, HACK and XXX
+	// markers above which a file is reported.  Zero turns the check off,
+	// but the markers are still counted.
+	MarkerThreshold int
+
+	// MagicNumbers turns on the magic number check, which counts the
+	// numeric literals of functions, other than 0, 1 and AllowedNumbers,
+	// that are not named by constants.
+	MagicNumbers         bool
+	MagicNumberThreshold int
+	AllowedNumbers       []string
+
+	// Panics turns on the panic check, which counts the calls to panic,
+	// outside of init and main functions and test files, and to recover.
+	Panics         bool
+	PanicThreshold int
+
+	// SwitchDefaults turns on the switch default check, which reports the
+	// switches with more cases than SwitchDefaultThreshold but no default.
+	SwitchDefaults         bool
+	SwitchDefaultThreshold int
+
+	// MaxLineLength turns on the line length check, which reports the
+	// lines wider than it.  TabWidth is the number of columns of a tab.
+	MaxLineLength int
+	TabWidth      int
+
+	// CommentThreshold turns on the comment density check, which reports
+	// the functions with more statements than it, and fewer comment lines
+	// per statement than CommentRatio.  Zero leaves it off.
+	CommentThreshold int
+	CommentRatio     float64
+
+	// HalsteadVolume, HalsteadDifficulty and HalsteadEffort are the
+	// thresholds of the Halstead metrics of functions.  Zero turns each
+	// of them off.
+	HalsteadVolume     float64
+	HalsteadDifficulty float64
+	HalsteadEffort     float64
+
+	// MinMaintainability turns on the maintainability check, which
+	// reports the functions and files with a Maintainability Index, from
+	// 0 to 100, below it.
+	MinMaintainability float64
+
+	// Disabled holds the checks that are turned off, by name.  Enable
+	// and Disable fill it from check names or IDs.
+	Disabled map[string]bool
+
+	// Metrics makes the summary hold the metrics of every function, not
+	// just the offenders.
+	Metrics bool
+
+	// IncludeGenerated turns on the checks for files with a
+	// "// Code generated ... DO NOT EDIT." header, which are skipped
+	// otherwise.
+	IncludeGenerated bool
+
+	// Severities sets the severity of the issues found by each check,
+	// by check name.  Checks not listed report errors.
+	Severities map[string]Severity
+
+	// SkipCgo skips the files that import "C", rather than analyzing
+	// them like other files.
+	SkipCgo bool
+
+	// IgnoreFuncs, if set, skips the functions whose name matches it,
+	// and OnlyFuncs the functions whose name doesn't.  Methods match by
+	// name or by name qualified by their receiver type, like T.String.
+	IgnoreFuncs *regexp.Regexp
+	OnlyFuncs   *regexp.Regexp
+
+	// Exclude holds patterns of file paths, relative to the working
+	// directory and separated by slashes, that Run skips.
+	Exclude []*regexp.Regexp
+
+	// GOOS, GOARCH and BuildTags, if any is set, restrict the analysis
+	// to the files built for that platform and those tags, going by
+	// their names and //go:build constraints.  GOOS and GOARCH default
+	// to those of the go tool.  With none set, every file is analyzed.
+	GOOS      string
+	GOARCH    string
+	BuildTags []string
+
+	// StdinFilename is the name Run reports for source read from stdin,
+	// which is asked for with a "-" argument.
+	StdinFilename string
+
+	// Baseline, if set, holds known issues that Run leaves out of the
+	// summary.
+	Baseline *Baseline
+
+	// Changes, if set, limits the issues Run reports to the functions
+	// with changed lines.
+	Changes Changes
+
+	// Cache, if set, keeps the results of every file Run analyzes, and
+	// reuses them while the file and the options stay the same.
+	Cache *Cache
+
+	// SkipDirs holds the names of the directories Run skips when
+	// walking recursive patterns like "./...", unless they are the
+	// root of the pattern.
+	SkipDirs []string
+
+	// ReadFile, if set, reads the files Run analyzes instead of
+	// os.ReadFile, like to analyze the contents staged in git rather
+	// than the working tree.
+	ReadFile func(filename string) ([]byte, error)
+
+	// Jobs is the number of files Run analyzes concurrently.  It
+	// defaults to GOMAXPROCS.
+	Jobs int
+
+	// Warn, if set, is called by Run for every offender as soon as it
+	// is found.
+	Warn func(*Offender)
+
+	// Reporter, if set, is passed every offender by Run as soon as it is
+	// found, after Warn.
+	Reporter Reporter
+
+	// SnippetLines, if above zero, is the number of lines quoted from the
+	// start and the end of the source of every offender, in its Snippet.
+	SnippetLines int
+}
+
+// DefaultOptions returns the thresholds splint uses unless told otherwise.
+func DefaultOptions() Options {
+	return Options{
+		StatementThreshold:       30,
+		InitThreshold:            10,
+		ParamThreshold:           5,
+		VariadicThreshold:        4,
+		ResultThreshold:          5,
+		ReturnThreshold:          8,
+		LocalThreshold:           15,
+		IfChainThreshold:         2,
+		IfBodyThreshold:          20,
+		SwitchCaseThreshold:      10,
+		TypeSwitchCaseThreshold:  10,
+		CaseBodyThreshold:        20,
+		LabelThreshold:           2,
+		ConditionThreshold:       5,
+		GuardThreshold:           80,
+		ChainThreshold:           4,
+		GoroutineThreshold:       3,
+		FanOutThreshold:          20,
+		FanInThreshold:           5,
+		ClosureDepthThreshold:    2,
+		SwitchDefaultThreshold:   2,
+		DiscardedErrorThreshold:  0,
+		CognitiveThreshold:       15,
+		DuplicateThreshold:       10,
+		DuplicateSimilarity:      90,
+		StructFieldThreshold:     20,
+		InterfaceMethodThreshold: 5,
+		MethodThreshold:          20,
+		FileLineThreshold:        1000,
+		FileFunctionThreshold:    50,
+		ImportThreshold:          20,
+		GlobalThreshold:          10,
+		TabWidth:                 4,
+		CommentRatio:             0.05,
+		SkipDirs:                 []string{"vendor", "testdata", ".git", "node_modules"},
+	}
+}
+
+// Parser parses go source files, looking for potentially complex
+// code.
+//
+//splint:ignore type-methods every check is a method
+type Parser struct {
+	filename string
+	first    bool
+	summary  *Summary
+	fileset  *token.FileSet
+	opts     Options
+	ignores  []*ignore
+	info     *types.Info
+	pkgPath  string
+	fn       *ast.FuncDecl
+	src      []byte
+	lines    [][]byte
+	comments []*ast.CommentGroup
+}
+
+// NewParser creates a splint parser for a file.
+func NewParser(filename string, summary *Summary, opts Options) *Parser {
+	if IsTestFile(filename) {
+		opts = opts.forTests()
+	}
+	return &Parser{filename: filename, first: true, summary: summary, opts: opts}
+}
+
+// position resolves pos, reporting it under the file name the parser was
+// given, which may be shorter than the one in the file set.
+func (p *Parser) position(pos token.Pos) token.Position {
+	position := p.fileset.Position(pos)
+	position.Filename = p.filename
+	return position
+}
+
+func (p *Parser) offender(function string, count int, node ast.Node) *Offender {
+	return p.offenderAt(function, count, node.Pos(), node.End())
+}
+
+// offenderAt is like offender, for an offender between pos and end.
+func (p *Parser) offenderAt(function string, count int, pos, end token.Pos) *Offender {
+	o := &Offender{
+		Filename: p.filename,
+		Function: function,
+		Count:    count,
+		Position: p.position(pos),
+		Pos:      pos,
+		End:      p.position(end),
+		Package:  p.pkgPath,
+	}
+	o.Span = LineRange{o.Position.Line, o.Position.Line}
+	if p.fn != nil {
+		o.Span = LineRange{p.fileset.Position(p.fn.Pos()).Line, p.fileset.Position(p.fn.End()).Line}
+	}
+	return o
+}
+
+// report adds an offender for a check to the summary, unless an ignore
+// directive covers it.
+func (p *Parser) report(check string, o *Offender, add func(*Offender)) {
+	if !p.opts.enabled(check) {
+		return
+	}
+	o.Check = check
+	o.ID = CheckID(check)
+	o.Severity = p.opts.severity(check)
+	o.Threshold = p.opts.threshold(o)
+	if reason, ok := p.suppressed(check, o.Pos); ok {
+		p.summary.addSuppressed(o, reason)
+		return
+	}
+	o.Snippet = p.snippet(o)
+	add(o)
+}
+
+// suppressed reports whether an ignore directive covers a check at pos,
+// and the reason it gives.
+func (p *Parser) suppressed(check string, pos token.Pos) (string, bool) {
+	for _, ig := range p.ignores {
+		if ig.covers(check, pos) {
+			return ig.reason, true
+		}
+	}
+	return "", false
+}
+
+func (p *Parser) examineFunc(x *ast.FuncDecl) {
+	p.countMethod(x)
+	if !p.opts.wantFunc(x) {
+		return
+	}
+	n := statementCount(x)
+	p.summary.pkg(p.pkgPath).addFunctions(1, n, n)
+	restore := p.overrideThresholds(x)
+	p.checkInit(x)
+	p.runChecks(x)
+	restore()
+	if x.Body != nil {
+		p.examineClosures(funcName(x), x.Body)
+	}
+}
+
+// runChecks runs the function checks on x.
+func (p *Parser) runChecks(x *ast.FuncDecl) {
+	p.fn = x
+	defer func() { p.fn = nil }()
+	p.checkSize(x)
+	p.checkSignature(x)
+	p.checkStatements(x)
+	p.checkCalls(x)
+	p.checkComplexity(x)
+	p.recordShape(x)
+	p.recordCalls(x)
+	p.runCustom(x)
+	if p.opts.Metrics {
+		p.measureFunc(x)
+	}
+}
+
+// checkSize runs the checks on the length of x and the number of its
+// returns, locals and labels.
+func (p *Parser) checkSize(x *ast.FuncDecl) {
+	p.checkFuncLength(x)
+	p.checkFuncLines(x)
+	p.checkReturnCount(x)
+	p.checkLocalCount(x)
+	p.checkLabels(x)
+}
+
+// checkCalls runs the checks on the calls and go statements of x.
+func (p *Parser) checkCalls(x *ast.FuncDecl) {
+	p.checkDiscardedErrors(x)
+	p.checkMethodChains(x)
+	p.checkGoroutines(x)
+	p.checkPanics(x)
+	p.checkExits(x)
+	p.checkFanOut(x)
+}
+
+// checkComplexity runs the checks measuring how hard x is to follow.
+func (p *Parser) checkComplexity(x *ast.FuncDecl) {
+	p.checkMagicNumbers(x)
+	p.checkCognitive(x)
+	p.checkComments(x)
+	p.checkHalstead(x)
+	p.checkMaintainability(x)
+}
+
+// checkSignature runs the checks on the params and results of x.
+func (p *Parser) checkSignature(x *ast.FuncDecl) {
+	p.checkParamCount(x)
+	p.checkBoolParams(x)
+	p.checkVariadic(x)
+	p.checkContext(x)
+	p.checkResultCount(x)
+	p.checkNamedResults(x)
+}
+
+// checkStatements runs the checks on the if, for, switch and select
+// statements of x, and their conditions.
+func (p *Parser) checkStatements(x *ast.FuncDecl) {
+	p.checkEmptyIfs(x)
+	p.checkEmptyBodies(x)
+	p.checkConditions(x)
+	p.checkGuardClause(x)
+	p.checkIfChains(x)
+	p.checkSwitchCases(x)
+	p.checkLongCases(x)
+	p.checkSwitchDefaults(x)
+	p.checkUnreachable(x)
+}
+
+func (p *Parser) examineDecls(tree *ast.File) {
+	for _, v := range tree.Decls {
+		switch x := v.(type) {
+		case *ast.FuncDecl:
+			p.examineFunc(x)
+		case *ast.GenDecl:
+			p.examineClosures("", x)
+		}
+	}
+}
+
+// Parse parses a file, looking for issues in functions.
+func (p *Parser) Parse() error {
+	src, err := p.opts.readFile(p.filename)
+	if err != nil {
+		return err
+	}
+	return p.parse(src)
+}
+
+// ParseSource is like Parse, but takes the contents of the file from src
+// instead of reading it.  The parser's filename is still used in positions.
+func (p *Parser) ParseSource(src []byte) error {
+	return p.parse(src)
+}
+
+func (p *Parser) parse(src []byte) error {
+	if !p.opts.builds(p.filename, src) {
+		p.summary.NumConstrained++
+		return nil
+	}
+	p.src = src
+	fileset := token.NewFileSet()
+	tree, err := parser.ParseFile(fileset, p.filename, src, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	p.Check(fileset, tree)
+	return nil
+}
+
+// Check looks for issues in the functions of an already parsed file.  The
+// file must have been parsed with comments for ignore directives and
+// generated file detection to work.
+func (p *Parser) Check(fileset *token.FileSet, tree *ast.File) {
+	if !p.opts.IncludeGenerated && ast.IsGenerated(tree) {
+		p.summary.NumGenerated++
+		return
+	}
+	if p.opts.SkipCgo && importsC(tree) {
+		p.summary.NumCgo++
+		return
+	}
+	p.fileset = fileset
+	if p.pkgPath == "" {
+		p.pkgPath = importPath(filepath.Dir(p.filename))
+	}
+	p.ignores = findIgnores(fileset, tree)
+	p.comments = tree.Comments
+	p.examineDecls(tree)
+	p.examineTypes(tree)
+	p.examineFile(tree)
+	p.runCustom(tree)
+}
+
+// CheckTypes is like Check, but lets the checks use the type information
+// of the file's package.
+func (p *Parser) CheckTypes(fileset *token.FileSet, tree *ast.File, info *types.Info) {
+	p.info = info
+	p.Check(fileset, tree)
+}
@@ -0,0 +1,64 @@
+package lint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+// magicCount counts the numeric literals of a function other than 0, 1 and
+// the allowed numbers, leaving out the ones naming constants.
+func (p *Parser) magicCount(x *ast.FuncDecl) int {
+	total := 0
+	inspectFunc(x, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.GenDecl:
+			return n.Tok != token.CONST
+		case *ast.BasicLit:
+			if p.isMagic(n) {
+				total++
+			}
+		}
+		return true
+	})
+	return total
+}
+
+func (p *Parser) isMagic(lit *ast.BasicLit) bool {
+	if lit.Kind != token.INT && lit.Kind != token.FLOAT && lit.Kind != token.IMAG {
+		return false
+	}
+	v := constant.MakeFromLiteral(lit.Value, lit.Kind, 0)
+	if v.Kind() == constant.Unknown || isNumber(v, "0") || isNumber(v, "1") {
+		return false
+	}
+	for _, allowed := range p.opts.AllowedNumbers {
+		if isNumber(v, allowed) {
+			return false
+		}
+	}
+	return true
+}
+
+// isNumber reports whether v is the number written as s, in any notation
+// go accepts.
+func isNumber(v constant.Value, s string) bool {
+	for _, kind := range []token.Token{token.INT, token.FLOAT, token.IMAG} {
+		if n := constant.MakeFromLiteral(s, kind, 0); n.Kind() != constant.Unknown {
+			return constant.Compare(v, token.EQL, n)
+		}
+	}
+	return false
+}
+
+func (p *Parser) checkMagicNumbers(x *ast.FuncDecl) {
+	if !p.opts.MagicNumbers {
+		return
+	}
+	numMagic := p.magicCount(x)
+	if numMagic <= p.opts.MagicNumberThreshold {
+		return
+	}
+
+	p.report(CheckMagicNumber, p.offender(funcName(x), numMagic, x), p.summary.addMagic)
+}
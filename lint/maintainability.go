@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"math"
+)
+
+// cyclomaticComplexity counts the paths through a function: one, plus one
+// for every branch and every && or ||.
+func cyclomaticComplexity(x *ast.FuncDecl) int {
+	total := 1
+	if x.Body == nil {
+		return total
+	}
+	ast.Inspect(x.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			total++
+		case *ast.CaseClause:
+			if n.List != nil {
+				total++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil {
+				total++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				total++
+			}
+		}
+		return true
+	})
+	return total
+}
+
+// maintainability holds what the Maintainability Index is computed from,
+// for a function or for all the functions of a file.
+type maintainability struct {
+	volume     float64
+	cyclomatic int
+	statements int
+}
+
+func measureMaintainability(x *ast.FuncDecl) maintainability {
+	return maintainability{halstead(x).Volume, cyclomaticComplexity(x), statementCount(x)}
+}
+
+func (m maintainability) plus(other maintainability) maintainability {
+	return maintainability{m.volume + other.volume, m.cyclomatic + other.cyclomatic, m.statements + other.statements}
+}
+
+// index is the classic Maintainability Index, with statements standing in
+// for lines of code, scaled from 0 to 100 the way Visual Studio does.
+// Lower is harder to maintain.
+func (m maintainability) index() float64 {
+	if m.statements == 0 {
+		return 100
+	}
+	mi := 171 - 5.2*math.Log(math.Max(m.volume, 1)) - 0.23*float64(m.cyclomatic) - 16.2*math.Log(float64(m.statements))
+	return math.Min(100, math.Max(0, mi*100/171))
+}
+
+// checkMaintainability reports the functions with a Maintainability
+// Index below opts.MinMaintainability.
+func (p *Parser) checkMaintainability(x *ast.FuncDecl) {
+	if p.opts.MinMaintainability <= 0 {
+		return
+	}
+	mi := measureMaintainability(x).index()
+	if mi >= p.opts.MinMaintainability {
+		return
+	}
+
+	p.report(CheckMaintainability, p.offender(funcName(x), int(math.Round(mi)), x), p.summary.addMaintainability)
+}
+
+// checkFileMaintainability is checkMaintainability for a whole file, with
+// the index computed from the totals of its functions.
+func (p *Parser) checkFileMaintainability(tree *ast.File) {
+	if p.opts.MinMaintainability <= 0 {
+		return
+	}
+	var m maintainability
+	for _, decl := range tree.Decls {
+		if x, ok := decl.(*ast.FuncDecl); ok {
+			m = m.plus(measureMaintainability(x))
+		}
+	}
+	mi := m.index()
+	if mi >= p.opts.MinMaintainability {
+		return
+	}
+
+	p.report(CheckMaintainability, p.fileOffender(int(math.Round(mi)), tree), p.summary.addMaintainability)
+}
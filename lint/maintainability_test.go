@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCyclomaticComplexity(t *testing.T) {
+	tests := []struct {
+		src  string
+		want int
+	}{
+		{"func f() {}", 1},
+		{"func f(a, b bool) {\n\tif a && b {\n\t}\n}", 3},
+		{"func f(s []int) {\n\tfor range s {\n\t}\n\tfor {\n\t}\n}", 3},
+		{"func f(n int) {\n\tswitch n {\n\tcase 1, 2:\n\tcase 3:\n\tdefault:\n\t}\n}", 3},
+		{"func f(c chan int) {\n\tselect {\n\tcase <-c:\n\tdefault:\n\t}\n}", 2},
+	}
+	for _, tt := range tests {
+		if got := cyclomaticComplexity(parseFunc(t, tt.src)); got != tt.want {
+			t.Errorf("cyclomaticComplexity(%q) = %d, want %d", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMaintainabilityIndex(t *testing.T) {
+	tests := []struct {
+		m    maintainability
+		want float64
+	}{
+		{maintainability{}, 100},
+		{maintainability{1, 1, 1}, (171 - 0.23) * 100 / 171},
+		{maintainability{math.Exp(10), 10, 20}, (171 - 52 - 2.3 - 16.2*math.Log(20)) * 100 / 171},
+		{maintainability{math.Exp(40), 100, 1000}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.index(); !near(got, tt.want) {
+			t.Errorf("%+v: index %g, want %g", tt.m, got, tt.want)
+		}
+	}
+}
+
+func TestCheckMaintainability(t *testing.T) {
+	opts := DefaultOptions()
+	opts.MinMaintainability = 60
+	found := make(map[string]int)
+	summary := &Summary{Warn: func(o *Offender) {
+		if o.Check == CheckMaintainability {
+			found[o.Function]++
+		}
+	}}
+	long := "func g(a, b, c int) int {\n" + strings.Repeat("\ta = a*b + c - a/b\n", 30) + "\treturn a\n}\n"
+	src := "package a\n\nfunc f() {}\n\n" + long
+	if err := NewParser("a.go", summary, opts).ParseSource([]byte(src)); err != nil {
+		t.Fatal(err)
+	}
+	if found["f"] != 0 || found["g"] != 1 || found[""] != 1 {
+		t.Errorf("maintainability issues %v, want g and the file", found)
+	}
+}
@@ -0,0 +1,65 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+)
+
+// markerRegexp matches the markers commonly left in comments for work
+// that still needs doing: at the start of the comment, or followed by a
+// colon or an opening parenthesis anywhere in it, so that prose merely
+// naming them is not counted.
+var markerRegexp = regexp.MustCompile(`^(?://|/\*)\s*(?:TODO|FIXME|HACK|XXX)\b|\b(?:TODO|FIXME|HACK|XXX)[:(]`)
+
+// commentMarkers counts the markers in the text of a comment.
+func commentMarkers(text string) int {
+	return len(markerRegexp.FindAllStringIndex(text, -1))
+}
+
+// markerCount counts the markers in the comments between from and to.
+func (p *Parser) markerCount(from, to token.Pos) int {
+	total := 0
+	for _, cg := range p.comments {
+		if cg.Pos() < from || cg.End() > to {
+			continue
+		}
+		for _, c := range cg.List {
+			total += commentMarkers(c.Text)
+		}
+	}
+	return total
+}
+
+// funcMarkers counts the markers in a function, doc comment included.
+func (p *Parser) funcMarkers(x *ast.FuncDecl) int {
+	from := x.Pos()
+	if x.Doc != nil {
+		from = x.Doc.Pos()
+	}
+	return p.markerCount(from, x.End())
+}
+
+// checkMarkers counts the markers of a file in the summary, and reports
+// the file if it has more than opts.MarkerThreshold of them.
+func (p *Parser) checkMarkers(tree *ast.File) {
+	numMarkers := p.markerCount(tree.FileStart, tree.FileEnd)
+	if numMarkers == 0 {
+		return
+	}
+	p.summary.addMarkers(p.filename, numMarkers)
+	if p.opts.MarkerThreshold <= 0 || numMarkers <= p.opts.MarkerThreshold {
+		return
+	}
+
+	p.report(CheckMarkers, p.fileOffender(numMarkers, tree), p.summary.addMarkerFile)
+}
+
+// addMarkers records the number of markers in a file.
+func (s *Summary) addMarkers(filename string, n int) {
+	if s.Markers == nil {
+		s.Markers = make(map[string]int)
+	}
+	s.Markers[filename] += n
+	s.NumMarkers += n
+}
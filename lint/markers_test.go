@@ -0,0 +1,26 @@
+package lint
+
+import "testing"
+
+func TestCommentMarkers(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"// TODO", 1},
+		{"// TODO handle errors", 1},
+		{"//FIXME: off by one", 1},
+		{"/* HACK until the API is fixed */", 1},
+		{"// XXX(bob): and TODO: tests", 2},
+		{"// retry, TODO(alice) make it configurable", 1},
+		{"// counts the TODO, FIXME, HACK and XXX markers", 0},
+		{"// a TODO list", 0},
+		{"// TODOS are counted elsewhere", 0},
+		{"// nothing to do", 0},
+	}
+	for _, tt := range tests {
+		if got := commentMarkers(tt.text); got != tt.want {
+			t.Errorf("commentMarkers(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+// typeMethods counts the methods of a type declared at the package level.
+// Methods may be declared in any file of the package, so types are only
+// checked once all the files are merged.
+type typeMethods struct {
+	Package  string
+	Name     string
+	Methods  int
+	Declared bool
+	Filename string
+	Position token.Position
+	Pos      token.Pos `json:"-"`
+	lateIgnore
+
+	// PointerMethods and ValueMethods name the methods by the kind of
+	// receiver they take, and Mixed covers them with an ignore directive
+	// for the mixed receivers check.
+	PointerMethods []string
+	ValueMethods   []string
+	Mixed          lateIgnore
+}
+
+// lateIgnore records, for an offender only found once every file is
+// merged, whether an ignore directive covers it and the reason it gives.
+type lateIgnore struct {
+	Ignored bool
+	Reason  string
+}
+
+func (s *Summary) typeMethods(pkg, name string) *typeMethods {
+	if s.types == nil {
+		s.types = make(map[string]*typeMethods)
+	}
+	key := pkg + "." + name
+	t, ok := s.types[key]
+	if !ok {
+		t = &typeMethods{Package: pkg, Name: name}
+		s.types[key] = t
+	}
+	return t
+}
+
+// mergeTypes adds the methods and declarations of types found in other
+// files.
+func (s *Summary) mergeTypes(types map[string]*typeMethods) {
+	for _, other := range types {
+		t := s.typeMethods(other.Package, other.Name)
+		t.Methods += other.Methods
+		pointers := append(t.PointerMethods, other.PointerMethods...)
+		values := append(t.ValueMethods, other.ValueMethods...)
+		if other.Declared {
+			methods := t.Methods
+			*t = *other
+			t.Methods = methods
+		}
+		t.PointerMethods, t.ValueMethods = pointers, values
+	}
+}
+
+// receiverType returns the name of the type of a method's receiver.
+func receiverType(x *ast.FuncDecl) string {
+	if x.Recv == nil || len(x.Recv.List) == 0 {
+		return ""
+	}
+	typ := x.Recv.List[0].Type
+	for {
+		switch t := typ.(type) {
+		case *ast.StarExpr:
+			typ = t.X
+		case *ast.ParenExpr:
+			typ = t.X
+		case *ast.IndexExpr:
+			typ = t.X
+		case *ast.IndexListExpr:
+			typ = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// countMethod counts a method towards its receiver type, and records the
+// kind of receiver it takes.
+func (p *Parser) countMethod(x *ast.FuncDecl) {
+	name := receiverType(x)
+	if name == "" {
+		return
+	}
+	t := p.summary.typeMethods(p.pkgPath, name)
+	t.Methods++
+	if pointerReceiver(x) {
+		t.PointerMethods = append(t.PointerMethods, x.Name.Name)
+	} else {
+		t.ValueMethods = append(t.ValueMethods, x.Name.Name)
+	}
+}
+
+// declareType records where a package level type is declared, and whether
+// an ignore directive covers its methods.
+func (p *Parser) declareType(spec *ast.TypeSpec) {
+	t := p.summary.typeMethods(p.pkgPath, spec.Name.Name)
+	t.Declared = true
+	t.Filename = p.filename
+	t.Position = p.position(spec.Name.Pos())
+	t.Pos = spec.Name.Pos()
+	t.Reason, t.Ignored = p.suppressed(CheckTypeMethods, spec.Name.Pos())
+	t.Mixed.Reason, t.Mixed.Ignored = p.suppressed(CheckMixedReceivers, spec.Name.Pos())
+}
+
+// CheckMethods reports the types declared with more methods than
+// opts.MethodThreshold, and those mixing pointer and value receivers, in
+// any of the files merged into the summary.  Run and RunPackages call it
+// once they have merged every file; other callers need to call it when
+// they are done.
+func (s *Summary) CheckMethods(opts Options) {
+	var keys []string
+	for key := range s.types {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t := s.types[key]
+		if t.Declared && t.Methods > opts.MethodThreshold {
+			s.mergeLate(t.offender(CheckTypeMethods, t.Methods, opts), t.lateIgnore, opts)
+		}
+	}
+	s.checkMixedReceivers(opts, keys)
+}
+
+// offender reports a type for a check, at its declaration.
+func (t *typeMethods) offender(check string, count int, opts Options) *Offender {
+	o := &Offender{
+		Filename: t.Filename,
+		Count:    count,
+		Position: t.Position,
+		Pos:      t.Pos,
+		Check:    check,
+		ID:       CheckID(check),
+		Severity: opts.severity(check),
+		Package:  t.Package,
+		Type:     t.Name,
+		End:      t.Position,
+		Span:     LineRange{t.Position.Line, t.Position.Line},
+	}
+	o.Threshold = opts.threshold(o)
+	return o
+}
+
+// mergeLate adds an offender found once every file was merged, unless its
+// check is off, it was ignored, is not part of the changes, or is in the
+// baseline.
+func (s *Summary) mergeLate(o *Offender, ig lateIgnore, opts Options) {
+	if !opts.enabled(o.Check) {
+		return
+	}
+	if ig.Ignored {
+		s.addSuppressed(o, ig.Reason)
+		return
+	}
+	if opts.Changes != nil && !opts.Changes.touches(o) {
+		s.NumUnchanged++
+		return
+	}
+	s.mergeOffenders([]*Offender{o}, opts.Baseline)
+}
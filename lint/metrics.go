@@ -0,0 +1,78 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// FunctionMetrics holds the measurements splint takes of a function,
+// whether or not they are over a threshold.
+type FunctionMetrics struct {
+	Filename   string
+	Function   string
+	Position   token.Position
+	Statements int
+	Lines      int
+	Params     int
+	Results    int
+	Returns    int
+	Locals     int
+	IfChain    int
+	Nesting    int
+	Cognitive  int
+
+	// Comments is the number of comment lines in the function, and
+	// CommentRatio the number of comment lines per statement.
+	Comments     int
+	CommentRatio float64
+
+	Halstead Halstead
+
+	// Cyclomatic is the cyclomatic complexity of the function, and
+	// Maintainability its Maintainability Index, from 0 to 100.
+	Cyclomatic      int
+	Maintainability float64
+
+	// Markers counts the TODO, FIXME, HACK and XXX markers in the
+	// function and its doc comment.
+	Markers int
+}
+
+// maxChainLength returns the length of the longest if/else chain in a
+// function.
+func maxChainLength(x *ast.FuncDecl) int {
+	longest := 0
+	ast.Inspect(x, func(node ast.Node) bool {
+		if y, ok := node.(*ast.IfStmt); ok {
+			if n := chainLength(y); n > longest {
+				longest = n
+			}
+			return false
+		}
+		return true
+	})
+	return longest
+}
+
+func (p *Parser) measureFunc(x *ast.FuncDecl) {
+	p.summary.Functions = append(p.summary.Functions, &FunctionMetrics{
+		Filename:        p.filename,
+		Function:        funcName(x),
+		Position:        p.position(x.Pos()),
+		Statements:      statementCount(x),
+		Lines:           p.lineCount(x),
+		Params:          x.Type.Params.NumFields(),
+		Results:         x.Type.Results.NumFields(),
+		Returns:         returnCount(x),
+		Locals:          localCount(x),
+		IfChain:         maxChainLength(x),
+		Nesting:         nestingDepth(x),
+		Cognitive:       cognitiveComplexity(x),
+		Comments:        p.commentLines(x),
+		CommentRatio:    p.commentRatio(x),
+		Halstead:        halstead(x),
+		Cyclomatic:      cyclomaticComplexity(x),
+		Maintainability: measureMaintainability(x).index(),
+		Markers:         p.funcMarkers(x),
+	})
+}
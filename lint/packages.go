@@ -0,0 +1,87 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const loadMode = packages.NeedName | packages.NeedFiles | packages.NeedCompiledGoFiles |
+	packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo
+
+// RunPackages is like Run, but loads whole packages with the go tool
+// instead of parsing single files.  It takes package patterns like
+// "./...", honors build constraints, and gives the checks access to type
+// information.
+func RunPackages(patterns []string, opts Options) (*Summary, error) {
+	cfg := &packages.Config{Mode: loadMode, Tests: !opts.IgnoreTestFiles}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{Warn: opts.warn()}
+	var errs []error
+	seen := make(map[string]bool)
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			errs = append(errs, e)
+		}
+		errs = append(errs, summary.mergePackage(pkg, seen, opts)...)
+	}
+	summary.CheckMethods(opts)
+	summary.CheckDuplicates(opts)
+	summary.CheckFanIn(opts)
+	return summary, errors.Join(errs...)
+}
+
+// relative shortens filename to a path relative to the working directory,
+// when it is below it.
+func relative(filename string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return filename
+	}
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filename
+	}
+	return rel
+}
+
+// mergePackage analyzes the files of a package that were not seen yet, as
+// test variants of a package repeat its files.  The files rewritten by cgo
+// are analyzed from their original source, without type information, so
+// that positions are right.  It returns the errors parsing them.
+func (s *Summary) mergePackage(pkg *packages.Package, seen map[string]bool, opts Options) []error {
+	var errs []error
+	for _, tree := range pkg.Syntax {
+		filename := relative(pkg.Fset.Position(tree.Pos()).Filename)
+		if seen[filename] || (opts.IgnoreTestFiles && IsTestFile(filename)) {
+			continue
+		}
+		seen[filename] = true
+		if opts.excluded(filename) {
+			s.NumExcluded++
+			continue
+		}
+		r := new(fileResult)
+		r.summary = &Summary{Warn: func(o *Offender) { r.found = append(r.found, o) }}
+		p := NewParser(filename, r.summary, opts)
+		p.pkgPath = pkg.PkgPath
+		if rewrittenByCgo(pkg.Fset, tree) {
+			if err := p.Parse(); err != nil {
+				errs = append(errs, fmt.Errorf("error parsing %s: %s", filename, err))
+				continue
+			}
+		} else {
+			p.CheckTypes(pkg.Fset, tree, pkg.TypesInfo)
+		}
+		s.merge(r, opts)
+	}
+	return errs
+}
@@ -0,0 +1,48 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// isBuiltinCall reports whether a call is to the named builtin function.
+// Without type information, it has to be spelled that way.
+func (p *Parser) isBuiltinCall(call *ast.CallExpr, name string) bool {
+	id, ok := ast.Unparen(call.Fun).(*ast.Ident)
+	if !ok || id.Name != name {
+		return false
+	}
+	if p.info != nil {
+		if _, ok := p.info.Uses[id].(*types.Builtin); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// panicCount counts the calls to panic and recover of a function, leaving
+// out function literals.  Panics are fine in init and main functions and
+// the function literals inside them, like calls ending the program, and
+// in test files.
+func (p *Parser) panicCount(x *ast.FuncDecl) int {
+	mayPanic := inMainOrInit(x) || IsTestFile(p.filename)
+	return countNodes(x, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		return p.isBuiltinCall(call, "recover") || (!mayPanic && p.isBuiltinCall(call, "panic"))
+	})
+}
+
+func (p *Parser) checkPanics(x *ast.FuncDecl) {
+	if !p.opts.Panics {
+		return
+	}
+	numPanics := p.panicCount(x)
+	if numPanics <= p.opts.PanicThreshold {
+		return
+	}
+
+	p.report(CheckPanics, p.offender(funcName(x), numPanics, x), p.summary.addPanics)
+}
@@ -0,0 +1,36 @@
+package lint
+
+import "testing"
+
+func TestPanicsAndExits(t *testing.T) {
+	tests := []struct {
+		name   string
+		src    string
+		panics int
+		exits  int
+	}{
+		{"function", "func f() {\n\tpanic(1)\n\tos.Exit(1)\n}\n", 1, 1},
+		{"main", "func main() {\n\tpanic(1)\n\tos.Exit(1)\n}\n", 0, 0},
+		{"init", "func init() {\n\tpanic(1)\n\tos.Exit(1)\n}\n", 0, 0},
+		{"closure in main", "func main() {\n\tgo func() {\n\t\tpanic(1)\n\t\tos.Exit(1)\n\t}()\n}\n", 0, 0},
+		{"closure in init", "func init() {\n\tdefer func() {\n\t\tpanic(1)\n\t\tos.Exit(1)\n\t}()\n}\n", 0, 0},
+		{"closure in function", "func f() {\n\tgo func() {\n\t\tpanic(1)\n\t\tos.Exit(1)\n\t}()\n}\n", 1, 1},
+		{"recover in main", "func main() {\n\tdefer func() {\n\t\trecover()\n\t}()\n}\n", 1, 0},
+		{"method named main", "type T struct{}\n\nfunc (T) main() {\n\tpanic(1)\n\tos.Exit(1)\n}\n", 1, 1},
+		{"TestMain", "func TestMain() {\n\tpanic(1)\n\tos.Exit(1)\n}\n", 1, 0},
+	}
+	for _, tt := range tests {
+		opts := DefaultOptions()
+		opts.Panics = true
+		found := make(map[string]int)
+		summary := &Summary{Warn: func(o *Offender) { found[o.Check]++ }}
+		src := "package main\n\nimport \"os\"\n\n" + tt.src
+		if err := NewParser("a.go", summary, opts).ParseSource([]byte(src)); err != nil {
+			t.Fatal(err)
+		}
+		if found[CheckPanics] != tt.panics || found[CheckExit] != tt.exits {
+			t.Errorf("%s: %d panic-recover and %d exit-outside-main issues, want %d and %d",
+				tt.name, found[CheckPanics], found[CheckExit], tt.panics, tt.exits)
+		}
+	}
+}
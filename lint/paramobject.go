@@ -0,0 +1,140 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// minParamObject is the number of params sharing a name prefix or a type
+// from which they are suggested as a struct of their own, rather than
+// along with all the others.
+const minParamObject = 3
+
+// ParamObject is a struct that could replace some of the params of a
+// function taking too many: the ones sharing a name prefix, like dbHost
+// and dbPort, or else a type, or else all of them but a leading context.
+// Reason tells which.
+type ParamObject struct {
+	Name   string
+	Fields []ParamField
+	Reason string
+}
+
+// ParamField is a field of a ParamObject, with the param it replaces.
+type ParamField struct {
+	Name  string
+	Type  string
+	Param string
+}
+
+// String describes the suggestion, like "dbHost, dbPort and dbUser share
+// the prefix db; consider passing them in a struct dbOptions".
+func (s *ParamObject) String() string {
+	var params []string
+	for _, f := range s.Fields {
+		params = append(params, f.Param)
+	}
+	return fmt.Sprintf("%s %s; consider passing them in a struct %s", joinNames(params), s.Reason, s.Name)
+}
+
+// param is a named param of a function, with its type as written.
+type param struct {
+	name, typ string
+}
+
+// namedParams lists the named params of a function, leaving out a
+// leading context.Context, which stays a param of its own.
+func (p *Parser) namedParams(x *ast.FuncDecl) []param {
+	var params []param
+	for i, f := range x.Type.Params.List {
+		if i == 0 && p.isContext(f.Type) {
+			continue
+		}
+		for _, name := range f.Names {
+			if name.Name != "_" {
+				params = append(params, param{name.Name, types.ExprString(f.Type)})
+			}
+		}
+	}
+	return params
+}
+
+// namePrefix returns the first word of a mixedCaps name, like "db" for
+// dbHost, or "" for names of a single word.
+func namePrefix(name string) string {
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			return name[:i]
+		}
+	}
+	return ""
+}
+
+// largestGroup returns the largest group of params with the same key, the
+// first in the order of the params when there is a tie, ignoring the empty
+// key.
+func largestGroup(params []param, key func(param) string) (string, []param) {
+	groups := make(map[string][]param)
+	var keys []string
+	for _, prm := range params {
+		k := key(prm)
+		if k == "" {
+			continue
+		}
+		if groups[k] == nil {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], prm)
+	}
+	sort.SliceStable(keys, func(i, j int) bool { return len(groups[keys[i]]) > len(groups[keys[j]]) })
+	if len(keys) == 0 {
+		return "", nil
+	}
+	return keys[0], groups[keys[0]]
+}
+
+// export returns name with its first letter in upper case.
+func export(name string) string {
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(r)) + name[size:]
+}
+
+// structName names the struct for the params of x after name, exported
+// when x is.
+func structName(x *ast.FuncDecl, name string) string {
+	if x.Name.IsExported() {
+		return export(name) + "Options"
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToLower(r)) + name[size:] + "Options"
+}
+
+// suggestParamObject proposes a struct for the params of a function taking
+// too many, named after their shared prefix or the function, and exported
+// when the function is.
+func (p *Parser) suggestParamObject(x *ast.FuncDecl) *ParamObject {
+	params := p.namedParams(x)
+	if prefix, group := largestGroup(params, func(prm param) string { return namePrefix(prm.name) }); len(group) >= minParamObject {
+		s := &ParamObject{Name: structName(x, prefix), Reason: "share the prefix " + prefix}
+		for _, prm := range group {
+			s.Fields = append(s.Fields, ParamField{strings.TrimPrefix(prm.name, prefix), prm.typ, prm.name})
+		}
+		return s
+	}
+	s := &ParamObject{Name: structName(x, x.Name.Name), Reason: "are too many"}
+	if typ, group := largestGroup(params, func(prm param) string { return prm.typ }); len(group) >= minParamObject && len(group) < len(params) {
+		params, s.Reason = group, "are all of type "+typ
+	}
+	if len(params) < minParamObject {
+		return nil
+	}
+	for _, prm := range params {
+		s.Fields = append(s.Fields, ParamField{export(prm.name), prm.typ, prm.name})
+	}
+	return s
+}
@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"fmt"
+	"sort"
+)
+
+// profiles adjust the default options to a level of strictness.
+var profiles = map[string]func(*Options){
+	"relaxed":      relaxed,
+	"default":      func(*Options) {},
+	"strict":       strict,
+	"gofmt-zealot": zealot,
+}
+
+// Profiles returns the names of the built-in profiles.
+func Profiles() []string {
+	var names []string
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the options of a built-in profile: "relaxed" for adopting
+// splint on an existing codebase, "default", "strict", and "gofmt-zealot",
+// which turns on every check with tight thresholds.
+func Profile(name string) (Options, error) {
+	apply, ok := profiles[name]
+	if !ok {
+		return Options{}, fmt.Errorf("unknown profile %q", name)
+	}
+	opts := DefaultOptions()
+	apply(&opts)
+	return opts, nil
+}
+
+//splint:ignore statement-count,duplicate a threshold for each check
+func relaxed(opts *Options) {
+	opts.StatementThreshold = 50
+	opts.InitThreshold = 20
+	opts.ParamThreshold = 8
+	opts.VariadicThreshold = 6
+	opts.ResultThreshold = 8
+	opts.ReturnThreshold = 12
+	opts.LocalThreshold = 25
+	opts.IfChainThreshold = 4
+	opts.IfBodyThreshold = 30
+	opts.SwitchCaseThreshold = 20
+	opts.TypeSwitchCaseThreshold = 20
+	opts.CaseBodyThreshold = 30
+	opts.LabelThreshold = 4
+	opts.ConditionThreshold = 8
+	opts.GuardThreshold = 90
+	opts.ChainThreshold = 6
+	opts.GoroutineThreshold = 5
+	opts.FanOutThreshold = 30
+	opts.FanInThreshold = 10
+	opts.ClosureDepthThreshold = 3
+	opts.DiscardedErrorThreshold = 2
+	opts.CognitiveThreshold = 25
+	opts.DuplicateThreshold = 20
+	opts.StructFieldThreshold = 30
+	opts.InterfaceMethodThreshold = 10
+	opts.MethodThreshold = 30
+	opts.FileLineThreshold = 2000
+	opts.FileFunctionThreshold = 80
+	opts.ImportThreshold = 30
+	opts.GlobalThreshold = 20
+}
+
+//splint:ignore statement-count,duplicate a threshold for each check
+func strict(opts *Options) {
+	opts.StatementThreshold = 20
+	opts.InitThreshold = 5
+	opts.LineThreshold = 60
+	opts.ParamThreshold = 4
+	opts.VariadicThreshold = 3
+	opts.ResultThreshold = 3
+	opts.ReturnThreshold = 5
+	opts.LocalThreshold = 10
+	opts.IfChainThreshold = 1
+	opts.IfBodyThreshold = 10
+	opts.SwitchCaseThreshold = 8
+	opts.TypeSwitchCaseThreshold = 8
+	opts.CaseBodyThreshold = 10
+	opts.LabelThreshold = 0
+	opts.ConditionThreshold = 3
+	opts.GuardThreshold = 70
+	opts.ChainThreshold = 3
+	opts.GoroutineThreshold = 2
+	opts.FanOutThreshold = 15
+	opts.FanInThreshold = 3
+	opts.ClosureDepthThreshold = 1
+	opts.CognitiveThreshold = 10
+	opts.DuplicateThreshold = 6
+	opts.StructFieldThreshold = 12
+	opts.InterfaceMethodThreshold = 4
+	opts.MethodThreshold = 15
+	opts.FileLineThreshold = 600
+	opts.FileFunctionThreshold = 30
+	opts.ImportThreshold = 15
+	opts.GlobalThreshold = 5
+	opts.MaxLineLength = 120
+	opts.MinMaintainability = 20
+}
+
+// zealot is strict, with tighter thresholds for function size and the
+// opt-in checks on.
+func zealot(opts *Options) {
+	strict(opts)
+	opts.StatementThreshold = 15
+	opts.LineThreshold = 40
+	opts.ParamThreshold = 3
+	opts.ResultThreshold = 2
+	opts.ReturnThreshold = 4
+	opts.LocalThreshold = 8
+	opts.CognitiveThreshold = 7
+	opts.FileLineThreshold = 400
+	opts.MagicNumbers = true
+	opts.Panics = true
+	opts.SwitchDefaults = true
+	opts.MaxLineLength = 100
+	opts.CommentThreshold = 15
+	opts.MinMaintainability = 30
+	opts.MarkerThreshold = 5
+}
@@ -0,0 +1,60 @@
+package lint
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+)
+
+// pointerReceiver reports whether a method takes a pointer receiver.
+func pointerReceiver(x *ast.FuncDecl) bool {
+	if x.Recv == nil || len(x.Recv.List) == 0 {
+		return false
+	}
+	_, ok := ast.Unparen(x.Recv.List[0].Type).(*ast.StarExpr)
+	return ok
+}
+
+// mixedReceivers returns the methods of a type taking the less common kind
+// of receiver, sorted, and that kind: "value" or "pointer".  Value
+// receivers are the odd ones out when there are as many of both, as a type
+// with some methods needing a pointer should take one in all of them.
+func (t *typeMethods) mixedReceivers() ([]string, string) {
+	if len(t.PointerMethods) == 0 || len(t.ValueMethods) == 0 {
+		return nil, ""
+	}
+	names, kind := t.ValueMethods, "value"
+	if len(t.ValueMethods) > len(t.PointerMethods) {
+		names, kind = t.PointerMethods, "pointer"
+	}
+	names = append([]string(nil), names...)
+	sort.Strings(names)
+	return names, kind
+}
+
+// checkMixedReceivers reports the types declared with methods on both
+// pointer and value receivers, listing the ones that differ from most.
+func (s *Summary) checkMixedReceivers(opts Options, keys []string) {
+	for _, key := range keys {
+		t := s.types[key]
+		names, kind := t.mixedReceivers()
+		if !t.Declared || names == nil {
+			continue
+		}
+		o := t.offender(CheckMixedReceivers, len(names), opts)
+		o.Metric = kind
+		o.Methods = names
+		s.mergeLate(o, t.Mixed, opts)
+	}
+}
+
+// receiversWarning describes the methods of a type on the less common kind
+// of receiver, like "type T mixes pointer and value receivers: String and
+// Len take a value".
+func (o *Offender) receiversWarning() {
+	verb := "takes"
+	if len(o.Methods) > 1 {
+		verb = "take"
+	}
+	o.message = fmt.Sprintf("type %s mixes pointer and value receivers: %s %s a %s", o.Type, joinNames(o.Methods), verb, o.Metric)
+}
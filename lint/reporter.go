@@ -0,0 +1,28 @@
+package lint
+
+// Reporter receives the results of a run, for the output formats and for
+// library users with sinks of their own.  Run and RunPackages pass it every
+// offender with Report as soon as it is found, in the order the results
+// are merged, like Options.Warn.  Finish is left to the caller, to call
+// once it is done with the summary, as it may add to it first, like the
+// Stats.
+type Reporter interface {
+	Report(*Offender)
+	Finish(*Summary) error
+}
+
+// warn returns the function passing offenders to Warn and to the Reporter,
+// whichever are set, or nil.
+func (opts *Options) warn() func(*Offender) {
+	switch {
+	case opts.Reporter == nil:
+		return opts.Warn
+	case opts.Warn == nil:
+		return opts.Reporter.Report
+	}
+	warn, report := opts.Warn, opts.Reporter.Report
+	return func(o *Offender) {
+		warn(o)
+		report(o)
+	}
+}
@@ -0,0 +1,122 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// resultNames returns the named results of a function, leaving out _.
+func resultNames(x *ast.FuncDecl) map[string]*ast.Ident {
+	names := make(map[string]*ast.Ident)
+	if x.Type.Results == nil {
+		return names
+	}
+	for _, f := range x.Type.Results.List {
+		for _, name := range f.Names {
+			if name.Name != "_" {
+				names[name.Name] = name
+			}
+		}
+	}
+	return names
+}
+
+// hasNakedReturn reports whether a function returns without values,
+// leaving out function literals.
+func hasNakedReturn(x *ast.FuncDecl) bool {
+	found := false
+	inspectFunc(x, func(node ast.Node) bool {
+		if ret, ok := node.(*ast.ReturnStmt); ok && len(ret.Results) == 0 {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// usedNames collects the identifiers of a function body, including its
+// function literals.
+func usedNames(body *ast.BlockStmt) map[string]bool {
+	used := make(map[string]bool)
+	ast.Inspect(body, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok {
+			used[id.Name] = true
+		}
+		return true
+	})
+	return used
+}
+
+// shadows collects the identifiers declaring a variable of the same name
+// as a named result in a block nested in the function body, or in the
+// body of a function literal, where it hides the result.  Declared at the
+// top of the body, := sets the result instead.
+type shadows struct {
+	names    map[string]*ast.Ident
+	topLevel map[ast.Stmt]bool
+	found    []*ast.Ident
+}
+
+func shadowed(x *ast.FuncDecl, names map[string]*ast.Ident) []*ast.Ident {
+	s := &shadows{names: names, topLevel: make(map[ast.Stmt]bool)}
+	for _, stmt := range x.Body.List {
+		s.topLevel[stmt] = true
+	}
+	ast.Inspect(x.Body, s.visit)
+	return s.found
+}
+
+func (s *shadows) declare(ids ...ast.Expr) {
+	for _, e := range ids {
+		if id, ok := e.(*ast.Ident); ok && s.names[id.Name] != nil {
+			s.found = append(s.found, id)
+		}
+	}
+}
+
+func (s *shadows) visit(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.AssignStmt:
+		if n.Tok == token.DEFINE && !s.topLevel[n] {
+			s.declare(n.Lhs...)
+		}
+	case *ast.RangeStmt:
+		if n.Tok == token.DEFINE {
+			s.declare(n.Key, n.Value)
+		}
+	case *ast.ValueSpec:
+		for _, id := range n.Names {
+			s.declare(id)
+		}
+	}
+	return true
+}
+
+// checkNamedResults looks for named results that are never used by name,
+// only returned by naked returns, and for variables shadowing a named
+// result.
+func (p *Parser) checkNamedResults(x *ast.FuncDecl) {
+	names := resultNames(x)
+	if len(names) == 0 || x.Body == nil {
+		return
+	}
+	if hasNakedReturn(x) {
+		used := usedNames(x.Body)
+		for _, f := range x.Type.Results.List {
+			for _, name := range f.Names {
+				if names[name.Name] != nil && !used[name.Name] {
+					p.reportNamedResult(x, name, "naked")
+				}
+			}
+		}
+	}
+	for _, id := range shadowed(x, names) {
+		p.reportNamedResult(x, id, "shadowed")
+	}
+}
+
+func (p *Parser) reportNamedResult(x *ast.FuncDecl, id *ast.Ident, metric string) {
+	o := p.offender(funcName(x), 0, id)
+	o.Metric = metric
+	p.report(CheckNamedResults, o, p.summary.addNamedResult)
+}
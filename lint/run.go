@@ -0,0 +1,304 @@
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Run analyzes the given go files, directories, and recursive patterns like
+// "./...", or stdin for a "-" argument, and returns a Summary of everything
+// found.  Files that cannot be read or parsed are skipped; their errors are
+// joined into the returned error, alongside a Summary of the files that
+// could be analyzed.
+//
+// Files are analyzed concurrently by opts.Jobs workers, but the results are
+// merged, and passed to opts.Warn and opts.Reporter, in the order of the
+// files.
+func Run(files []string, opts Options) (*Summary, error) {
+	summary := &Summary{Warn: opts.warn()}
+	e := expand(files, &opts)
+	paths, errs := e.paths, e.errs
+	summary.NumExcluded = e.excluded
+	if len(e.skipped) > 0 {
+		summary.Skipped = e.skipped
+	}
+	for i, result := range analyzeAll(paths, opts) {
+		r := <-result
+		if r.err != nil {
+			errs = append(errs, fmt.Errorf("error parsing %s: %s", paths[i], r.err))
+			continue
+		}
+		summary.merge(r, opts)
+	}
+	summary.CheckMethods(opts)
+	summary.CheckDuplicates(opts)
+	return summary, errors.Join(errs...)
+}
+
+// Expand turns the arguments of Run into the list of files it would
+// analyze, and also returns how many files were excluded.
+func Expand(files []string, opts Options) ([]string, int, error) {
+	e := expand(files, &opts)
+	return e.paths, e.excluded, errors.Join(e.errs...)
+}
+
+// expansion is the list of files of the arguments of Run, with the number
+// of files excluded, and of skipped directories by name.
+type expansion struct {
+	paths    []string
+	excluded int
+	skipped  map[string]int
+	archives archiveFiles
+	errs     []error
+}
+
+// expand lists the files of the arguments of Run.  The go files of
+// archives are read in memory, and opts.ReadFile set to read them.
+func expand(files []string, opts *Options) *expansion {
+	e := &expansion{skipped: make(map[string]int), archives: make(archiveFiles)}
+	for _, arg := range files {
+		if arg == "-" {
+			e.paths = append(e.paths, arg)
+			continue
+		}
+		found, err := e.goFiles(arg, opts.SkipDirs)
+		if err != nil {
+			e.errs = append(e.errs, fmt.Errorf("error reading %s: %s", arg, err))
+			continue
+		}
+		e.add(found, opts)
+	}
+	if len(e.archives) > 0 {
+		opts.ReadFile = e.archives.readFile(*opts)
+	}
+	return e
+}
+
+// goFiles expands an argument like GoFiles, reading the go files of
+// archives, and skipping the directories named in skip inside archives and
+// below the root of patterns.
+func (e *expansion) goFiles(arg string, skip []string) ([]string, error) {
+	if IsArchive(arg) {
+		return e.archives.add(arg, skip, e.skipped)
+	}
+	if root, ok := patternRoot(arg); ok {
+		return walkDir(root, skip, e.skipped)
+	}
+	return GoFiles(arg)
+}
+
+// add adds the files found for an argument, leaving out test files with
+// opts.IgnoreTestFiles and counting excluded files.
+func (e *expansion) add(found []string, opts *Options) {
+	for _, filename := range found {
+		if opts.IgnoreTestFiles && IsTestFile(filename) {
+			continue
+		}
+		if opts.excluded(filename) {
+			e.excluded++
+			continue
+		}
+		e.paths = append(e.paths, filename)
+	}
+}
+
+// fileResult is what a worker found in a single file, with its offenders
+// in the order they were found.
+type fileResult struct {
+	summary *Summary
+	found   []*Offender
+	err     error
+}
+
+func analyzeFile(filename string, opts Options) *fileResult {
+	if filename != "-" && opts.Cache != nil {
+		return opts.Cache.analyze(filename, opts)
+	}
+	r := new(fileResult)
+	r.summary = &Summary{Warn: func(o *Offender) { r.found = append(r.found, o) }}
+	if filename == "-" {
+		r.err = analyzeStdin(r.summary, opts)
+	} else {
+		r.err = NewParser(filename, r.summary, opts).Parse()
+	}
+	return r
+}
+
+func analyzeStdin(summary *Summary, opts Options) error {
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return err
+	}
+	filename := opts.StdinFilename
+	if filename == "" {
+		filename = "<stdin>"
+	}
+	return NewParser(filename, summary, opts).ParseSource(src)
+}
+
+// analyzeAll starts a pool of workers analyzing the files, and returns a
+// channel per file that receives its result.
+func analyzeAll(paths []string, opts Options) []chan *fileResult {
+	results := make([]chan *fileResult, len(paths))
+	for i := range results {
+		results[i] = make(chan *fileResult, 1)
+	}
+	jobs := make(chan int)
+	workers := opts.Jobs
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	//splint:ignore goroutine-in-loop a fixed pool of workers
+	for w := 0; w < workers; w++ {
+		go func() {
+			for i := range jobs {
+				results[i] <- analyzeFile(paths[i], opts)
+			}
+		}()
+	}
+	go func() {
+		for i := range paths {
+			jobs <- i
+		}
+		close(jobs)
+	}()
+	return results
+}
+
+// readFile reads a file with opts.ReadFile, or os.ReadFile if it is not
+// set.
+func (opts *Options) readFile(filename string) ([]byte, error) {
+	if opts.ReadFile != nil {
+		return opts.ReadFile(filename)
+	}
+	return os.ReadFile(filename)
+}
+
+func (opts *Options) excluded(filename string) bool {
+	name := filepath.ToSlash(filepath.Clean(filename))
+	for _, re := range opts.Exclude {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTestFile reports whether filename is a go test file.
+func IsTestFile(filename string) bool {
+	return strings.HasSuffix(filepath.Base(filename), "_test.go")
+}
+
+func isGoFile(filename string) bool {
+	return filepath.Ext(filename) == ".go"
+}
+
+// GoFiles expands a command line argument into the go files it refers to.
+// A directory yields the go files directly inside it, and a pattern ending
+// in "/..." (like "./...") yields every go file in the tree below it.
+func GoFiles(arg string) ([]string, error) {
+	if root, ok := patternRoot(arg); ok {
+		return walkDir(root, nil, nil)
+	}
+
+	info, err := os.Stat(arg)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{arg}, nil
+	}
+
+	entries, err := os.ReadDir(arg)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isGoFile(e.Name()) {
+			files = append(files, filepath.Join(arg, e.Name()))
+		}
+	}
+	return files, nil
+}
+
+// patternRoot returns the directory a recursive pattern like "./..." walks,
+// and whether arg is one.
+func patternRoot(arg string) (string, bool) {
+	if arg != "..." && !strings.HasSuffix(arg, "/...") {
+		return "", false
+	}
+	root := strings.TrimSuffix(strings.TrimSuffix(arg, "..."), "/")
+	if root == "" {
+		root = "."
+	}
+	return root, true
+}
+
+// walkDir lists the go files in the tree below root, skipping the
+// directories below it named in skip, which are counted in skipped by
+// name.
+func walkDir(root string, skip []string, skipped map[string]int) ([]string, error) {
+	var files []string
+	err := walkTree(root, skip, skipped, func(p string, d os.DirEntry) {
+		if !d.IsDir() && isGoFile(p) {
+			files = append(files, p)
+		}
+	})
+	return files, err
+}
+
+// walkTree calls visit for every file and directory in the tree below
+// root, skipping the directories below it named in skip without walking
+// them, and counting them in skipped by name.
+func walkTree(root string, skip []string, skipped map[string]int, visit func(string, os.DirEntry)) error {
+	return filepath.WalkDir(root, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && p != root && contains(skip, d.Name()) {
+			skipped[d.Name()]++
+			return filepath.SkipDir
+		}
+		visit(p, d)
+		return nil
+	})
+}
+
+// WatchDirs lists the directories to watch for changes to the files the
+// arguments of Run refer to: the directories given, those holding the
+// files given, and every directory below the root of a pattern like
+// "./..." except the ones named in opts.SkipDirs.
+func WatchDirs(args []string, opts Options) ([]string, error) {
+	var dirs []string
+	for _, arg := range args {
+		if root, ok := patternRoot(arg); ok {
+			err := walkTree(root, opts.SkipDirs, make(map[string]int), func(p string, d os.DirEntry) {
+				if d.IsDir() {
+					dirs = append(dirs, p)
+				}
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		dir := arg
+		if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+			dir = filepath.Dir(arg)
+		}
+		dirs = append(dirs, dir)
+	}
+	return dirs, nil
+}
+
+// SkipsDir reports whether Run skips directories called name when walking
+// patterns like "./...".
+func (opts *Options) SkipsDir(name string) bool {
+	return contains(opts.SkipDirs, name)
+}
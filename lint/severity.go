@@ -0,0 +1,32 @@
+package lint
+
+import "fmt"
+
+// Severity tells how much an issue matters.  Only errors make splint exit
+// with a non-zero status.
+type Severity string
+
+// The severities a check can be given.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// ParseSeverity checks that s names a severity.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity %q", s)
+}
+
+// severity returns the severity configured for a check, which is an error
+// unless set otherwise.
+func (opts *Options) severity(check string) Severity {
+	if sev, ok := opts.Severities[check]; ok {
+		return sev
+	}
+	return SeverityError
+}
@@ -0,0 +1,41 @@
+package lint
+
+import (
+	"bytes"
+	"strings"
+)
+
+// SnippetLine is a line of source quoted in the Snippet of an offender.
+type SnippetLine struct {
+	Line int
+	Text string
+}
+
+// snippet quotes the first and last opts.SnippetLines lines of the source
+// of an offender, like the function or if statement it reports, or all of
+// them if there are no more than twice as many.  Lines in between are left
+// out, which shows as a gap in the line numbers.
+func (p *Parser) snippet(o *Offender) []SnippetLine {
+	n := p.opts.SnippetLines
+	if n <= 0 || o.Position.Line < 1 {
+		return nil
+	}
+	src, err := p.source()
+	if err != nil {
+		return nil
+	}
+	if p.lines == nil {
+		p.lines = bytes.Split(src, []byte("\n"))
+	}
+	first, last := o.Position.Line, max(o.End.Line, o.Position.Line)
+	last = min(last, len(p.lines))
+	var quoted []SnippetLine
+	for line := first; line <= last; line++ {
+		if line > first+n-1 && line < last-n+1 {
+			line = last - n + 1
+		}
+		text := strings.TrimRight(string(p.lines[line-1]), "\r")
+		quoted = append(quoted, SnippetLine{line, text})
+	}
+	return quoted
+}
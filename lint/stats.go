@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"go/ast"
+	"math"
+	"sort"
+)
+
+// nesting finds the deepest nesting of control structures and function
+// literals in a function.  else and else if branches are at the level of
+// their if.
+type nesting struct {
+	deepest int
+}
+
+func nestingDepth(x *ast.FuncDecl) int {
+	n := new(nesting)
+	if x.Body != nil {
+		n.walk(x.Body, 0)
+	}
+	return n.deepest
+}
+
+func (n *nesting) enter(depth int) {
+	if depth > n.deepest {
+		n.deepest = depth
+	}
+}
+
+func (n *nesting) walk(root ast.Node, depth int) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		if node == root {
+			return true
+		}
+		switch x := node.(type) {
+		case *ast.IfStmt:
+			n.visitIf(x, depth)
+			return false
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt, *ast.FuncLit:
+			n.enter(depth + 1)
+			n.walk(node, depth+1)
+			return false
+		}
+		return true
+	})
+}
+
+func (n *nesting) visitIf(x *ast.IfStmt, depth int) {
+	n.enter(depth + 1)
+	n.walk(x.Body, depth+1)
+	switch e := x.Else.(type) {
+	case *ast.IfStmt:
+		n.visitIf(e, depth)
+	case *ast.BlockStmt:
+		n.walk(e, depth+1)
+	}
+}
+
+// Bucket counts the functions with a metric between From and To,
+// inclusive.
+type Bucket struct {
+	From  int
+	To    int
+	Count int
+}
+
+// Distribution describes the values a metric takes over every function of
+// a run, with its percentiles and a histogram of buckets doubling in size.
+type Distribution struct {
+	Metric    string
+	P50       int
+	P90       int
+	P99       int
+	Max       int
+	Histogram []Bucket
+}
+
+// Measuring returns the options for a pass that only measures functions,
+// like the one finding the thresholds given as percentiles: metrics are
+// collected, and issues are neither reported nor matched against the
+// baseline or the changes, so the baseline is not used up before the real
+// run.
+func (opts *Options) Measuring() Options {
+	measure := *opts
+	measure.Metrics = true
+	measure.Warn = nil
+	measure.Reporter = nil
+	measure.Baseline = nil
+	measure.Changes = nil
+	return measure
+}
+
+// Percentile returns the nearest-rank percentile p of sorted values.
+func Percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// histogram counts the values in the buckets 0, 1, 2-3, 4-7, and so on up
+// to the largest value.
+func histogram(sorted []int) []Bucket {
+	var buckets []Bucket
+	for _, v := range sorted {
+		for len(buckets) == 0 || v > buckets[len(buckets)-1].To {
+			from := 0
+			if len(buckets) > 0 {
+				from = buckets[len(buckets)-1].To + 1
+			}
+			buckets = append(buckets, Bucket{From: from, To: max(from*2-1, from)})
+		}
+		buckets[len(buckets)-1].Count++
+	}
+	return buckets
+}
+
+// NewDistribution computes the distribution of a metric over the given
+// functions.
+func NewDistribution(metric string, functions []*FunctionMetrics, value func(*FunctionMetrics) int) *Distribution {
+	values := make([]int, len(functions))
+	for i, m := range functions {
+		values[i] = value(m)
+	}
+	sort.Ints(values)
+	d := &Distribution{
+		Metric:    metric,
+		P50:       Percentile(values, 50),
+		P90:       Percentile(values, 90),
+		P99:       Percentile(values, 99),
+		Histogram: histogram(values),
+	}
+	if len(values) > 0 {
+		d.Max = values[len(values)-1]
+	}
+	return d
+}
+
+// NewStats computes the distributions of the length, parameter count and
+// nesting depth of the functions in a summary, which must have been
+// collected with Options.Metrics.
+func NewStats(s *Summary) []*Distribution {
+	return []*Distribution{
+		NewDistribution("statements", s.Functions, func(m *FunctionMetrics) int { return m.Statements }),
+		NewDistribution("params", s.Functions, func(m *FunctionMetrics) int { return m.Params }),
+		NewDistribution("nesting", s.Functions, func(m *FunctionMetrics) int { return m.Nesting }),
+	}
+}
@@ -0,0 +1,553 @@
+package lint
+
+import (
+	"fmt"
+	"go/token"
+	"time"
+)
+
+// Offender contains the file, function, position, and count of
+// a block of code that splint has recognized as an issue.
+//
+//splint:ignore struct-fields the details each check reports
+type Offender struct {
+	Filename string
+	Function string
+	Count    int
+	Position token.Position
+	Pos      token.Pos `json:"-"`
+
+	// End is where the offending function, statement or declaration
+	// ends, and Span the lines of the function holding the offender, or
+	// of the file or the type for the checks on files and types.
+	End  token.Position
+	Span LineRange
+
+	Check    string
+	Severity Severity
+	Package  string
+
+	// ID is the stable identifier of the check, like "SPL001", empty for
+	// custom checks.
+	ID string `json:",omitempty"`
+
+	// Type is the name of the type declaration holding the offender,
+	// for the checks on types.
+	Type string `json:",omitempty"`
+
+	// Imports breaks down the imports of a file, for the import count
+	// check.
+	Imports *ImportCounts `json:",omitempty"`
+
+	// Threshold is the threshold the count went over, for the checks
+	// that have one.
+	Threshold *float64 `json:",omitempty"`
+
+	// Metric names the measurement over its threshold, for the checks
+	// with several of them.
+	Metric string `json:",omitempty"`
+
+	// Duplicates lists the positions and names of the other functions
+	// with the same structure, for the duplicate check.
+	Duplicates []string `json:",omitempty"`
+
+	// Methods lists the methods taking the less common kind of receiver,
+	// for the mixed receivers check.
+	Methods []string `json:",omitempty"`
+
+	// Suggestion is a run of statements that could be extracted from a
+	// function that is too long, if one was found.
+	Suggestion *Suggestion `json:",omitempty"`
+
+	// ParamObject is a struct that could replace params of a function
+	// taking too many, if one was found.
+	ParamObject *ParamObject `json:",omitempty"`
+
+	// Snippet quotes the first and last lines of the offending source,
+	// when asked for with Options.SnippetLines.
+	Snippet []SnippetLine `json:",omitempty"`
+
+	// Blame is the last change to the lines of the function holding the
+	// offender, when asked for.
+	Blame *Blame `json:",omitempty"`
+
+	message string
+}
+
+func (o *Offender) warning(msg string) {
+	o.message = fmt.Sprintf("function %s %s: %d", o.Function, msg, o.Count)
+}
+
+func (o *Offender) fileWarning(msg string) {
+	o.message = fmt.Sprintf("file %s: %d", msg, o.Count)
+}
+
+func (o *Offender) typeWarning(kind, msg string) {
+	o.message = fmt.Sprintf("%s %s %s: %d", kind, o.Type, msg, o.Count)
+}
+
+func (o *Offender) lineWarning(msg string) {
+	o.message = fmt.Sprintf("line %s: %d", msg, o.Count)
+}
+
+func (o *Offender) warnNoCount(msg string) {
+	o.message = fmt.Sprintf("function %s %s", o.Function, msg)
+}
+
+// Message describes the issue, without the position.
+func (o *Offender) Message() string {
+	return o.message
+}
+
+// String formats the offender the way splint prints warnings, followed by
+// the ID of the check.  Issues that are not errors are marked with their
+// severity.
+func (o *Offender) String() string {
+	msg := o.message
+	if o.ID != "" {
+		msg += " (" + o.ID + ")"
+	}
+	if o.Severity != "" && o.Severity != SeverityError {
+		return fmt.Sprintf("%s:\t%s: %s", o.Position, o.Severity, msg)
+	}
+	return fmt.Sprintf("%s:\t%s", o.Position, msg)
+}
+
+// ImportCounts counts the imports of a file from the standard library,
+// and from anywhere else.
+type ImportCounts struct {
+	Stdlib     int
+	ThirdParty int
+}
+
+// Suppression is an offender that a //splint:ignore directive has
+// silenced, with the reason given in the directive.
+type Suppression struct {
+	*Offender
+	Reason string
+}
+
+// Blame is the most recent commit to change the lines of an offender, as
+// found by git blame.
+type Blame struct {
+	Commit string
+	Author string
+	Email  string
+	Date   time.Time
+}
+
+// Override is a threshold raised for a function by a //splint:threshold
+// directive, with the reason given in the directive.
+type Override struct {
+	Position  token.Position
+	Function  string
+	Check     string
+	Threshold int
+	Reason    string
+}
+
+// PackageSummary rolls up the functions and offenders of a package.
+// Inits counts its init functions, and offenders are counted by check.
+type PackageSummary struct {
+	Functions     int
+	Statements    int
+	AvgStatements float64
+	MaxStatements int
+	Inits         int
+	Offenders     map[string]int
+}
+
+func (s *Summary) pkg(path string) *PackageSummary {
+	if s.Packages == nil {
+		s.Packages = make(map[string]*PackageSummary)
+	}
+	p, ok := s.Packages[path]
+	if !ok {
+		p = &PackageSummary{Offenders: make(map[string]int)}
+		s.Packages[path] = p
+	}
+	return p
+}
+
+// addFunctions counts n functions with the given total and maximum
+// statement counts.
+func (p *PackageSummary) addFunctions(n, statements, max int) {
+	p.Functions += n
+	p.Statements += statements
+	if max > p.MaxStatements {
+		p.MaxStatements = max
+	}
+	if p.Functions > 0 {
+		p.AvgStatements = float64(p.Statements) / float64(p.Functions)
+	}
+}
+
+// Summary is a collection of Offenders for all the different
+// checks that splint performs.
+//
+//splint:ignore struct-fields,type-methods a list, a count and an add method for each check
+type Summary struct {
+	Statement      []*Offender
+	Lines          []*Offender
+	Inits          []*Offender
+	Param          []*Offender
+	Variadics      []*Offender
+	Contexts       []*Offender
+	Result         []*Offender
+	Returns        []*Offender
+	Locals         []*Offender
+	EmptyIfs       []*Offender
+	EmptyElses     []*Offender
+	EmptyFors      []*Offender
+	EmptySwitches  []*Offender
+	EmptySelects   []*Offender
+	Conditions     []*Offender
+	GuardClauses   []*Offender
+	NamedResults   []*Offender
+	MethodChains   []*Offender
+	Goroutines     []*Offender
+	GoInLoops      []*Offender
+	Panics         []*Offender
+	Exits          []*Offender
+	FanOuts        []*Offender
+	FanIns         []*Offender
+	Unreachable    []*Offender
+	NoDefaults     []*Offender
+	TypeSwitches   []*Offender
+	DeepClosures   []*Offender
+	IfChains       []*Offender
+	BoolParams     []*Offender
+	LongIfs        []*Offender
+	Switches       []*Offender
+	LongCases      []*Offender
+	Labels         []*Offender
+	DiscardedErrs  []*Offender
+	MagicNumbers   []*Offender
+	Cognitive      []*Offender
+	Duplicates     []*Offender
+	Uncommented    []*Offender
+	Halstead       []*Offender
+	Unmaintainable []*Offender
+	LongFiles      []*Offender
+	FileFunctions  []*Offender
+	Imports        []*Offender
+	Globals        []*Offender
+	Structs        []*Offender
+	Interfaces     []*Offender
+	TypeMethods    []*Offender
+	MixedReceivers []*Offender
+	LongLines      []*Offender
+	MarkerFiles    []*Offender
+
+	// Custom holds the offenders found by custom checks, by check name.
+	Custom map[string][]*Offender `json:",omitempty"`
+
+	// Packages rolls up the functions and offenders of each package, by
+	// import path.
+	Packages map[string]*PackageSummary
+
+	// Functions holds the metrics of every function, when asked for
+	// with Options.Metrics.
+	Functions []*FunctionMetrics `json:",omitempty"`
+
+	// Stats holds the distributions of function metrics over the run,
+	// when computed with NewStats.
+	Stats []*Distribution `json:",omitempty"`
+
+	// Files groups the offenders by file, with their counts, when
+	// computed with NewFileSummaries.
+	Files []*FileSummary `json:",omitempty"`
+
+	// Markers counts the TODO, FIXME, HACK and XXX markers left in the
+	// comments of each file, by file name, and NumMarkers counts them all.
+	Markers    map[string]int `json:",omitempty"`
+	NumMarkers int
+
+	// Suppressed holds the offenders silenced by //splint:ignore
+	// directives, so they are not forgotten about.
+	Suppressed []*Suppression
+
+	// Overrides holds the thresholds raised by //splint:threshold
+	// directives, as an audit trail of the exceptions made.
+	Overrides    []*Override `json:",omitempty"`
+	NumOverrides int
+
+	// redundant, but using these for easy json output
+	NumAboveStatementThreshold       int
+	NumAboveLineThreshold            int
+	NumAboveInitThreshold            int
+	NumAboveParamThreshold           int
+	NumVariadics                     int
+	NumMisplacedContexts             int
+	NumAboveResultThreshold          int
+	NumAboveReturnThreshold          int
+	NumAboveLocalThreshold           int
+	NumIfChains                      int
+	NumEmptyIfs                      int
+	NumEmptyElses                    int
+	NumEmptyFors                     int
+	NumEmptySwitches                 int
+	NumEmptySelects                  int
+	NumComplexConditions             int
+	NumWrappedInIf                   int
+	NumNamedResultIssues             int
+	NumLongChains                    int
+	NumAboveGoroutineThreshold       int
+	NumGoInLoops                     int
+	NumWithPanics                    int
+	NumExits                         int
+	NumAboveFanOutThreshold          int
+	NumAboveFanInThreshold           int
+	NumUnreachable                   int
+	NumWithoutDefault                int
+	NumAboveTypeSwitchCaseThreshold  int
+	NumDeepClosures                  int
+	NumWithBoolParams                int
+	NumLongIfs                       int
+	NumLongSwitches                  int
+	NumLongCases                     int
+	NumAboveLabelThreshold           int
+	NumDiscardingErrors              int
+	NumWithMagicNumbers              int
+	NumAboveCognitiveThreshold       int
+	NumDuplicates                    int
+	NumUncommented                   int
+	NumAboveHalsteadThreshold        int
+	NumBelowMaintainabilityThreshold int
+	NumLongFiles                     int
+	NumAboveFileFunctionThreshold    int
+	NumAboveImportThreshold          int
+	NumAboveGlobalThreshold          int
+	NumAboveStructFieldThreshold     int
+	NumAboveInterfaceMethodThreshold int
+	NumAboveMethodThreshold          int
+	NumMixedReceivers                int
+	NumLongLines                     int
+	NumAboveMarkerThreshold          int
+	NumSuppressed                    int
+	NumBaselined                     int
+	NumExcluded                      int
+	NumGenerated                     int
+	NumConstrained                   int
+	NumCgo                           int
+	NumUnchanged                     int
+
+	// Tests counts the offenders found in test files, by check, so they
+	// can be told apart from those of the code under test.
+	Tests map[string]int `json:",omitempty"`
+
+	// Skipped counts the directories skipped by Options.SkipDirs, by
+	// directory name.
+	Skipped map[string]int `json:",omitempty"`
+
+	// Warn, if set, is called for every offender as soon as it is found.
+	Warn func(*Offender) `json:"-"`
+
+	types        map[string]*typeMethods
+	shapes       map[string][]*funcShape
+	callSites    map[string]int
+	complexFuncs map[string]*complexFunc
+}
+
+// IsClean checks if there are some issues to be reported
+func (s *Summary) IsClean() bool {
+	for _, section := range s.Sections() {
+		if len(section.Offenders) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Section is the list of offenders found by a single check.  Check is a
+// short name for the check, and Name describes the section.
+type Section struct {
+	Check     string
+	Name      string
+	Offenders []*Offender
+}
+
+// Sections returns the offenders grouped by check, in a fixed order: the
+// built-in checks first, then the custom checks by name.
+func (s *Summary) Sections() []Section {
+	sections := s.builtinSections()
+	for _, c := range CustomChecks() {
+		sections = append(sections, Section{c.Name(), c.Doc(), s.Custom[c.Name()]})
+	}
+	return sections
+}
+
+func (s *Summary) builtinSections() []Section {
+	return []Section{
+		{CheckStatementCount, "Functions above statement threshold", s.Statement},
+		{CheckLineCount, "Functions above line threshold", s.Lines},
+		{CheckInitLength, "Init functions above statement threshold", s.Inits},
+		{CheckParamCount, "Functions above param threshold", s.Param},
+		{CheckVariadic, "Functions misusing variadic params", s.Variadics},
+		{CheckContext, "Functions with misplaced context params", s.Contexts},
+		{CheckResultCount, "Functions above result threshold", s.Result},
+		{CheckReturnCount, "Functions above return threshold", s.Returns},
+		{CheckLocalCount, "Functions above local variable threshold", s.Locals},
+		{CheckIfChain, "Long if/else chains", s.IfChains},
+		{CheckEmptyIf, "Empty if bodies", s.EmptyIfs},
+		{CheckEmptyElse, "Empty else branches", s.EmptyElses},
+		{CheckEmptyFor, "Empty for bodies", s.EmptyFors},
+		{CheckEmptySwitch, "Switches without cases", s.EmptySwitches},
+		{CheckEmptySelect, "Selects without cases", s.EmptySelects},
+		{CheckUnreachable, "Unreachable statements", s.Unreachable},
+		{CheckCondition, "Complex conditions", s.Conditions},
+		{CheckGuardClause, "Functions wrapped in an if", s.GuardClauses},
+		{CheckNamedResults, "Misused named results", s.NamedResults},
+		{CheckMethodChain, "Long method chains", s.MethodChains},
+		{CheckGoroutines, "Functions above goroutine threshold", s.Goroutines},
+		{CheckGoInLoop, "Goroutines started in loops", s.GoInLoops},
+		{CheckPanics, "Functions using panic and recover", s.Panics},
+		{CheckExit, "Exits outside of main", s.Exits},
+		{CheckFanOut, "Functions above fan-out threshold", s.FanOuts},
+		{CheckFanIn, "Complex functions above fan-in threshold", s.FanIns},
+		{CheckLongIf, "Long if bodies", s.LongIfs},
+		{CheckSwitchCases, "Switches above case threshold", s.Switches},
+		{CheckTypeSwitchCases, "Type switches above case threshold", s.TypeSwitches},
+		{CheckSwitchDefault, "Switches without default", s.NoDefaults},
+		{CheckLongCase, "Long case bodies", s.LongCases},
+		{CheckLabels, "Functions above goto and label threshold", s.Labels},
+		{CheckClosureDepth, "Deeply nested function literals", s.DeepClosures},
+		{CheckDiscardedError, "Functions discarding errors", s.DiscardedErrs},
+		{CheckMagicNumber, "Functions with magic numbers", s.MagicNumbers},
+		{CheckBoolParam, "Functions with bool params", s.BoolParams},
+		{CheckCognitiveComplexity, "Functions above cognitive complexity threshold", s.Cognitive},
+		{CheckDuplicate, "Duplicate functions", s.Duplicates},
+		{CheckCommentDensity, "Long functions with few comments", s.Uncommented},
+		{CheckHalstead, "Functions above Halstead thresholds", s.Halstead},
+		{CheckMaintainability, "Functions and files below maintainability threshold", s.Unmaintainable},
+		{CheckFileLength, "Files above line threshold", s.LongFiles},
+		{CheckFileFunctions, "Files above function threshold", s.FileFunctions},
+		{CheckImports, "Files above import threshold", s.Imports},
+		{CheckGlobals, "Files above package variable threshold", s.Globals},
+		{CheckStructFields, "Structs above field threshold", s.Structs},
+		{CheckInterfaceMethods, "Interfaces above method threshold", s.Interfaces},
+		{CheckTypeMethods, "Types above method threshold", s.TypeMethods},
+		{CheckMixedReceivers, "Types mixing pointer and value receivers", s.MixedReceivers},
+		{CheckLineLength, "Long lines", s.LongLines},
+		{CheckMarkers, "Files above TODO marker threshold", s.MarkerFiles},
+	}
+}
+
+// HasErrors checks if any of the given checks found an issue with error
+// severity.
+func (s *Summary) HasErrors(checks ...string) bool {
+	for _, section := range s.Sections() {
+		for _, check := range checks {
+			if section.Check == check && hasErrors(section.Offenders) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasErrors(offenders []*Offender) bool {
+	for _, o := range offenders {
+		if o.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// merge adds what was found in a single file to the summary, leaving out
+// the offenders in the baseline, and those in unchanged functions.
+func (s *Summary) merge(r *fileResult, opts Options) {
+	found := r.found
+	if opts.Changes != nil {
+		found = found[:0:0]
+		for _, o := range r.found {
+			if opts.Changes.touches(o) {
+				found = append(found, o)
+			} else {
+				s.NumUnchanged++
+			}
+		}
+	}
+	s.mergeOffenders(found, opts.Baseline)
+	s.mergeCounts(r.summary)
+}
+
+// mergedLate holds the checks whose offenders may be spread over several
+// summaries, found once they are all merged.
+var mergedLate = map[string]bool{
+	CheckTypeMethods:    true,
+	CheckMixedReceivers: true,
+	CheckDuplicate:      true,
+}
+
+// Merge adds everything in other, a summary of different files, to s.
+// Offenders recorded in baseline, if not nil, are left out.  So are the
+// types with too many methods or mixed receivers and the duplicate
+// functions, as they may be spread over both summaries: call CheckMethods
+// and CheckDuplicates once everything is merged.
+func (s *Summary) Merge(other *Summary, baseline *Baseline) {
+	for _, section := range other.Sections() {
+		if !mergedLate[section.Check] {
+			s.mergeOffenders(section.Offenders, baseline)
+		}
+	}
+	s.mergeCounts(other)
+}
+
+func (s *Summary) mergeOffenders(offenders []*Offender, baseline *Baseline) {
+	for _, o := range offenders {
+		if baseline != nil && baseline.consume(o) {
+			s.NumBaselined++
+			continue
+		}
+		s.adder(o.Check)(o)
+	}
+}
+
+func (s *Summary) mergeCounts(other *Summary) {
+	for _, sup := range other.Suppressed {
+		s.addSuppressed(sup.Offender, sup.Reason)
+	}
+	for _, ov := range other.Overrides {
+		s.addOverride(ov)
+	}
+	s.Functions = append(s.Functions, other.Functions...)
+	s.mergeTypes(other.types)
+	s.mergeShapes(other.shapes)
+	s.mergeCalls(other)
+	for filename, n := range other.Markers {
+		s.addMarkers(filename, n)
+	}
+	// offenders were counted again as they were added
+	for path, p := range other.Packages {
+		s.pkg(path).addFunctions(p.Functions, p.Statements, p.MaxStatements)
+		s.pkg(path).Inits += p.Inits
+	}
+	s.NumBaselined += other.NumBaselined
+	s.NumExcluded += other.NumExcluded
+	for name, n := range other.Skipped {
+		if s.Skipped == nil {
+			s.Skipped = make(map[string]int)
+		}
+		s.Skipped[name] += n
+	}
+	s.NumGenerated += other.NumGenerated
+	s.NumConstrained += other.NumConstrained
+	s.NumCgo += other.NumCgo
+	s.NumUnchanged += other.NumUnchanged
+}
+
+// record counts an offender in its package, and passes it to Warn.
+func (s *Summary) record(o *Offender) {
+	s.pkg(o.Package).Offenders[o.Check]++
+	if IsTestFile(o.Filename) {
+		if s.Tests == nil {
+			s.Tests = make(map[string]int)
+		}
+		s.Tests[o.Check]++
+	}
+	if s.Warn != nil {
+		s.Warn(o)
+	}
+}
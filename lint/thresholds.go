@@ -0,0 +1,111 @@
+package lint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// thresholds holds the option each check compares its counts with, for
+// reporting it and for overriding it with //splint:threshold directives.
+var thresholds = map[string]func(*Options) *int{
+	CheckStatementCount:      func(o *Options) *int { return &o.StatementThreshold },
+	CheckLineCount:           func(o *Options) *int { return &o.LineThreshold },
+	CheckInitLength:          func(o *Options) *int { return &o.InitThreshold },
+	CheckParamCount:          func(o *Options) *int { return &o.ParamThreshold },
+	CheckVariadic:            func(o *Options) *int { return &o.VariadicThreshold },
+	CheckResultCount:         func(o *Options) *int { return &o.ResultThreshold },
+	CheckReturnCount:         func(o *Options) *int { return &o.ReturnThreshold },
+	CheckLocalCount:          func(o *Options) *int { return &o.LocalThreshold },
+	CheckIfChain:             func(o *Options) *int { return &o.IfChainThreshold },
+	CheckLongIf:              func(o *Options) *int { return &o.IfBodyThreshold },
+	CheckSwitchCases:         func(o *Options) *int { return &o.SwitchCaseThreshold },
+	CheckLongCase:            func(o *Options) *int { return &o.CaseBodyThreshold },
+	CheckLabels:              func(o *Options) *int { return &o.LabelThreshold },
+	CheckCondition:           func(o *Options) *int { return &o.ConditionThreshold },
+	CheckGuardClause:         func(o *Options) *int { return &o.GuardThreshold },
+	CheckMethodChain:         func(o *Options) *int { return &o.ChainThreshold },
+	CheckGoroutines:          func(o *Options) *int { return &o.GoroutineThreshold },
+	CheckPanics:              func(o *Options) *int { return &o.PanicThreshold },
+	CheckFanOut:              func(o *Options) *int { return &o.FanOutThreshold },
+	CheckFanIn:               func(o *Options) *int { return &o.FanInThreshold },
+	CheckSwitchDefault:       func(o *Options) *int { return &o.SwitchDefaultThreshold },
+	CheckTypeSwitchCases:     func(o *Options) *int { return &o.TypeSwitchCaseThreshold },
+	CheckClosureDepth:        func(o *Options) *int { return &o.ClosureDepthThreshold },
+	CheckDiscardedError:      func(o *Options) *int { return &o.DiscardedErrorThreshold },
+	CheckMagicNumber:         func(o *Options) *int { return &o.MagicNumberThreshold },
+	CheckCognitiveComplexity: func(o *Options) *int { return &o.CognitiveThreshold },
+	CheckFileLength:          func(o *Options) *int { return &o.FileLineThreshold },
+	CheckFileFunctions:       func(o *Options) *int { return &o.FileFunctionThreshold },
+	CheckImports:             func(o *Options) *int { return &o.ImportThreshold },
+	CheckGlobals:             func(o *Options) *int { return &o.GlobalThreshold },
+	CheckStructFields:        func(o *Options) *int { return &o.StructFieldThreshold },
+	CheckInterfaceMethods:    func(o *Options) *int { return &o.InterfaceMethodThreshold },
+	CheckTypeMethods:         func(o *Options) *int { return &o.MethodThreshold },
+	CheckLineLength:          func(o *Options) *int { return &o.MaxLineLength },
+	CheckMarkers:             func(o *Options) *int { return &o.MarkerThreshold },
+}
+
+// parseThreshold parses an entry like "statements=80" into the check it
+// sets the threshold of, given by name, short name, plural short name or
+// ID, and the threshold.
+func parseThreshold(entry string) (string, int, error) {
+	name, value, _ := strings.Cut(entry, "=")
+	check, ok := LookupCheck(name)
+	if !ok {
+		check, ok = LookupCheck(strings.TrimSuffix(name, "s"))
+	}
+	if !ok || thresholds[check] == nil {
+		return "", 0, fmt.Errorf("no check with a threshold named %q", name)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return "", 0, fmt.Errorf("bad threshold %q for %s", value, name)
+	}
+	return check, n, nil
+}
+
+// ParseThresholds parses a comma-separated list of thresholds like
+// "statements=80,lines=300" into thresholds by check, as used by
+// Options.TestThresholds.
+func ParseThresholds(list string) (map[string]int, error) {
+	set := make(map[string]int)
+	for _, entry := range strings.Split(list, ",") {
+		check, n, err := parseThreshold(entry)
+		if err != nil {
+			return nil, err
+		}
+		set[check] = n
+	}
+	return set, nil
+}
+
+// forTests returns the options for a test file, with the thresholds of
+// TestThresholds.
+func (opts *Options) forTests() Options {
+	test := *opts
+	for check, n := range opts.TestThresholds {
+		*thresholds[check](&test) = n
+	}
+	return test
+}
+
+// threshold returns the threshold an offender went over, or under for the
+// maintainability check, or nil for the checks without one.  Variadic
+// catch-alls have none, unlike variadic params with too many others.
+func (opts *Options) threshold(o *Offender) *float64 {
+	var t float64
+	switch {
+	case o.Check == CheckHalstead:
+		t = map[string]float64{"volume": opts.HalsteadVolume, "difficulty": opts.HalsteadDifficulty, "effort": opts.HalsteadEffort}[o.Metric]
+	case o.Check == CheckMaintainability:
+		t = opts.MinMaintainability
+	case o.Check == CheckVariadic && o.Count == 0:
+		return nil
+	case thresholds[o.Check] != nil:
+		t = float64(*thresholds[o.Check](opts))
+	default:
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,68 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// examineTypes runs the checks on the type declarations of a file,
+// including the ones local to functions.
+func (p *Parser) examineTypes(tree *ast.File) {
+	for _, decl := range tree.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.TYPE {
+			for _, spec := range gen.Specs {
+				p.declareType(spec.(*ast.TypeSpec))
+			}
+		}
+	}
+	ast.Inspect(tree, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.TypeSpec); ok {
+			p.examineType(spec)
+		}
+		return true
+	})
+}
+
+func (p *Parser) examineType(spec *ast.TypeSpec) {
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		p.checkStructFields(spec, t)
+	case *ast.InterfaceType:
+		p.checkInterfaceMethods(spec, t)
+	}
+}
+
+// typeOffender is like offender, for an issue with a type declaration.
+func (p *Parser) typeOffender(spec *ast.TypeSpec, count int) *Offender {
+	o := p.offenderAt("", count, spec.Name.Pos(), spec.End())
+	o.Type = spec.Name.Name
+	o.Span = LineRange{p.fileset.Position(spec.Pos()).Line, p.fileset.Position(spec.End()).Line}
+	return o
+}
+
+// checkStructFields counts the fields of a struct, with every name of a
+// field list counting as one field.
+func (p *Parser) checkStructFields(spec *ast.TypeSpec, t *ast.StructType) {
+	numFields := t.Fields.NumFields()
+	if numFields <= p.opts.StructFieldThreshold {
+		return
+	}
+
+	p.report(CheckStructFields, p.typeOffender(spec, numFields), p.summary.addStruct)
+}
+
+// checkInterfaceMethods counts the methods declared by an interface.
+// Embedded interfaces and type constraints are not counted.
+func (p *Parser) checkInterfaceMethods(spec *ast.TypeSpec, t *ast.InterfaceType) {
+	numMethods := 0
+	for _, f := range t.Methods.List {
+		if _, ok := f.Type.(*ast.FuncType); ok {
+			numMethods += len(f.Names)
+		}
+	}
+	if numMethods <= p.opts.InterfaceMethodThreshold {
+		return
+	}
+
+	p.report(CheckInterfaceMethods, p.typeOffender(spec, numMethods), p.summary.addInterface)
+}
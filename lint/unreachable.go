@@ -0,0 +1,56 @@
+package lint
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// terminates reports whether control never goes past a statement: a
+// return, a break, continue or goto, or a call to panic, maybe labeled.
+func (p *Parser) terminates(stmt ast.Stmt) bool {
+	switch s := stmt.(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.LabeledStmt:
+		return p.terminates(s.Stmt)
+	case *ast.BranchStmt:
+		return s.Tok != token.FALLTHROUGH
+	case *ast.ExprStmt:
+		call, ok := s.X.(*ast.CallExpr)
+		return ok && p.isBuiltinCall(call, "panic")
+	}
+	return false
+}
+
+// unreachable returns the first statement of a list that follows one
+// control never goes past, or nil.  A labeled statement can still be
+// reached with goto.
+func (p *Parser) unreachable(list []ast.Stmt) ast.Stmt {
+	for i, stmt := range list[:max(len(list)-1, 0)] {
+		next := list[i+1]
+		if _, labeled := next.(*ast.LabeledStmt); p.terminates(stmt) && !labeled {
+			return next
+		}
+	}
+	return nil
+}
+
+// checkUnreachable looks for statements after a return, break, continue,
+// goto or panic in the same block, reporting the first of each block.
+func (p *Parser) checkUnreachable(x *ast.FuncDecl) {
+	inspectFunc(x, func(node ast.Node) bool {
+		var list []ast.Stmt
+		switch n := node.(type) {
+		case *ast.BlockStmt:
+			list = n.List
+		case *ast.CaseClause:
+			list = n.Body
+		case *ast.CommClause:
+			list = n.Body
+		}
+		if stmt := p.unreachable(list); stmt != nil {
+			p.report(CheckUnreachable, p.offender(funcName(x), 0, stmt), p.summary.addUnreachable)
+		}
+		return true
+	})
+}
@@ -0,0 +1,42 @@
+package lint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUnreachable(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []int // the lines of the unreachable statements
+	}{
+		{"return", "\treturn\n\tg()\n", []int{5}},
+		{"panic", "\tpanic(1)\n\tg()\n\tg()\n", []int{5}},
+		{"last", "\tg()\n\treturn\n", nil},
+		{"break", "\tfor {\n\t\tbreak\n\t\tg()\n\t}\n", []int{6}},
+		{"continue in case", "\tfor {\n\t\tswitch {\n\t\tcase true:\n\t\t\tcontinue\n\t\t\tg()\n\t\t}\n\t}\n", []int{8}},
+		{"select", "\tselect {\n\tdefault:\n\t\treturn\n\t\tg()\n\t}\n", []int{7}},
+		{"fallthrough", "\tswitch {\n\tcase true:\n\t\tfallthrough\n\tdefault:\n\t}\n", nil},
+		{"label", "\tgoto L\nL:\n\tg()\n", nil},
+		{"labeled return", "L:\n\treturn\n\tg()\n\tgoto L\n", []int{6}},
+		{"each block", "\tif true {\n\t\treturn\n\t\tg()\n\t}\n\treturn\n\tg()\n", []int{6, 9}},
+		{"function literal", "\tg = func() {\n\t\treturn\n\t\tg()\n\t}\n", []int{6}},
+	}
+	for _, tt := range tests {
+		var lines []int
+		summary := &Summary{Warn: func(o *Offender) {
+			if o.Check == CheckUnreachable {
+				lines = append(lines, o.Position.Line)
+			}
+		}}
+		src := "package a\n\nfunc f() {\n" + tt.body + "}\n\nvar g func()\n"
+		if err := NewParser("a.go", summary, DefaultOptions()).ParseSource([]byte(src)); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		slices.Sort(lines)
+		if !slices.Equal(lines, tt.want) {
+			t.Errorf("%s: unreachable statements on lines %v, want %v", tt.name, lines, tt.want)
+		}
+	}
+}
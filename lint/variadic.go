@@ -0,0 +1,44 @@
+package lint
+
+import (
+	"go/ast"
+	"go/types"
+)
+
+// checkVariadic looks for functions taking a variadic parameter along with
+// too many other parameters, and for variadic catch-alls of interface{}.
+func (p *Parser) checkVariadic(x *ast.FuncDecl) {
+	params := x.Type.Params.List
+	if len(params) == 0 {
+		return
+	}
+	last := params[len(params)-1]
+	ellipsis, ok := last.Type.(*ast.Ellipsis)
+	if !ok {
+		return
+	}
+	if others := x.Type.Params.NumFields() - 1; others > p.opts.VariadicThreshold {
+		p.report(CheckVariadic, p.offender(funcName(x), others, x), p.summary.addVariadic)
+	}
+	if p.isEmptyInterface(ellipsis.Elt) {
+		p.report(CheckVariadic, p.offender(funcName(x), 0, last), p.summary.addVariadic)
+	}
+}
+
+// isEmptyInterface reports whether typ is interface{} or any, or with type
+// information, any type without methods.
+func (p *Parser) isEmptyInterface(typ ast.Expr) bool {
+	if p.info != nil {
+		if t := p.info.TypeOf(typ); t != nil {
+			iface, ok := t.Underlying().(*types.Interface)
+			return ok && iface.Empty()
+		}
+	}
+	switch t := typ.(type) {
+	case *ast.InterfaceType:
+		return len(t.Methods.List) == 0
+	case *ast.Ident:
+		return t.Name == "any"
+	}
+	return false
+}
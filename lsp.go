@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/agflow/splint/lint"
+)
+
+// lspServer is a minimal language server that publishes splint issues as
+// diagnostics for the documents an editor opens or changes.  It only
+// supports full document sync.
+type lspServer struct {
+	in   *bufio.Reader
+	out  io.Writer
+	opts lint.Options
+}
+
+type lspMessage struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method,omitempty"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+	Result  interface{}      `json:"result,omitempty"`
+	Error   *lspError        `json:"error,omitempty"`
+}
+
+type lspError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspDocument struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspDocumentParams struct {
+	TextDocument   lspDocument `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspPublishParams struct {
+	URI         string          `json:"uri"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+}
+
+const (
+	lspMethodNotFound = -32601
+	lspSyncFull       = 1
+)
+
+var lspSeverity = map[lint.Severity]int{
+	lint.SeverityError:   1,
+	lint.SeverityWarning: 2,
+	lint.SeverityInfo:    3,
+}
+
+// serveLSP runs a language server on stdin and stdout until the client
+// asks it to exit.
+func serveLSP(opts lint.Options) error {
+	// the documents an editor opens are checked whatever their platform
+	opts.GOOS, opts.GOARCH, opts.BuildTags = "", "", nil
+	s := &lspServer{in: bufio.NewReader(os.Stdin), out: os.Stdout, opts: opts}
+	for {
+		msg, err := s.read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		if err := s.handle(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *lspServer) read() (*lspMessage, error) {
+	length, err := readContentLength(s.in)
+	if err != nil {
+		return nil, err
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, err
+	}
+	msg := new(lspMessage)
+	if err := json.Unmarshal(body, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// readContentLength reads the header of a message, up to the empty line
+// ending it, and returns the length of the body it announces.
+func readContentLength(in *bufio.Reader) (int, error) {
+	length := -1
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return 0, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+			if length, err = strconv.Atoi(strings.TrimSpace(v)); err != nil {
+				return 0, fmt.Errorf("bad Content-Length: %s", err)
+			}
+		}
+	}
+	if length < 0 {
+		return 0, fmt.Errorf("missing Content-Length")
+	}
+	return length, nil
+}
+
+func (s *lspServer) write(msg *lspMessage) error {
+	msg.JSONRPC = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	return err
+}
+
+func (s *lspServer) handle(msg *lspMessage) error {
+	var params lspDocumentParams
+	switch msg.Method {
+	case "initialize":
+		return s.write(&lspMessage{ID: msg.ID, Result: map[string]interface{}{
+			"capabilities": map[string]interface{}{"textDocumentSync": lspSyncFull},
+			"serverInfo":   map[string]string{"name": "splint"},
+		}})
+	case "shutdown":
+		return s.write(&lspMessage{ID: msg.ID, Result: json.RawMessage("null")})
+	case "textDocument/didOpen", "textDocument/didChange", "textDocument/didClose":
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return err
+		}
+	default:
+		if msg.ID != nil {
+			return s.write(&lspMessage{ID: msg.ID, Error: &lspError{lspMethodNotFound, "method not found: " + msg.Method}})
+		}
+		return nil
+	}
+
+	doc := params.TextDocument
+	if n := len(params.ContentChanges); n > 0 {
+		doc.Text = params.ContentChanges[n-1].Text
+	}
+	diagnostics := []lspDiagnostic{}
+	if msg.Method != "textDocument/didClose" {
+		diagnostics = s.diagnose(doc)
+	}
+	return s.write(&lspMessage{Method: "textDocument/publishDiagnostics", Params: mustMarshal(lspPublishParams{doc.URI, diagnostics})})
+}
+
+// lspPositionAt converts a line and a column in bytes, counted from 1 the
+// way go/token does, into a position in lines, the document split at each
+// newline.  LSP counts columns in UTF-16 code units.
+func lspPositionAt(lines []string, line, column int) lspPosition {
+	character := column - 1
+	if line >= 1 && line <= len(lines) && character >= 0 && character <= len(lines[line-1]) {
+		character = 0
+		for _, r := range lines[line-1][:column-1] {
+			character += max(utf16.RuneLen(r), 1)
+		}
+	}
+	return lspPosition{line - 1, character}
+}
+
+// lspEnd is the end of the range of a diagnostic, the end of the offending
+// node when known, or else the start of the next line.
+func lspEnd(lines []string, o *lint.Offender) lspPosition {
+	if o.End.Line == 0 {
+		return lspPosition{o.Position.Line, 0}
+	}
+	return lspPositionAt(lines, o.End.Line, o.End.Column)
+}
+
+// diagnose analyzes a document.  Documents that don't parse get no
+// diagnostics, since the editor already shows the syntax errors.  The
+// checks spanning files, for duplicate functions and the methods of a
+// type, only see the document, so types and copies spread over several
+// files are left out.
+func (s *lspServer) diagnose(doc lspDocument) []lspDiagnostic {
+	diagnostics := []lspDiagnostic{}
+	lines := strings.Split(doc.Text, "\n")
+	summary := &lint.Summary{Warn: func(o *lint.Offender) {
+		diagnostics = append(diagnostics, lspDiagnostic{
+			Range: lspRange{
+				Start: lspPositionAt(lines, o.Position.Line, o.Position.Column),
+				End:   lspEnd(lines, o),
+			},
+			Severity: lspSeverity[o.Severity],
+			Code:     o.Check,
+			Source:   "splint",
+			Message:  o.Message(),
+		})
+	}}
+	if lint.NewParser(uriFilename(doc.URI), summary, s.opts).ParseSource([]byte(doc.Text)) == nil {
+		summary.CheckMethods(s.opts)
+		summary.CheckDuplicates(s.opts)
+	}
+	return diagnostics
+}
+
+func uriFilename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
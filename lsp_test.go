@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/agflow/splint/lint"
+)
+
+func TestLSPPositionAt(t *testing.T) {
+	lines := []string{"package a", `var s = "héllo" + x`, `var r = "😀" + x`, ""}
+	tests := []struct {
+		line, column int
+		want         lspPosition
+	}{
+		{1, 1, lspPosition{0, 0}},
+		{1, 9, lspPosition{0, 8}},
+		{2, 19, lspPosition{1, 17}},
+		{2, 20, lspPosition{1, 18}},
+		{3, 18, lspPosition{2, 15}},
+		{3, 19, lspPosition{2, 16}},
+		{4, 1, lspPosition{3, 0}},
+		{5, 3, lspPosition{4, 2}},
+	}
+	for _, tt := range tests {
+		if got := lspPositionAt(lines, tt.line, tt.column); got != tt.want {
+			t.Errorf("lspPositionAt(%d, %d) = %v, want %v", tt.line, tt.column, got, tt.want)
+		}
+	}
+}
+
+func TestReadContentLength(t *testing.T) {
+	tests := []struct {
+		header string
+		want   int
+		err    bool
+	}{
+		{"Content-Length: 12\r\n\r\n", 12, false},
+		{"Content-Type: application/vscode-jsonrpc; charset=utf-8\r\nContent-Length: 3\r\n\r\n", 3, false},
+		{"Content-Length: twelve\r\n\r\n", 0, true},
+		{"Content-Type: text/plain\r\n\r\n", 0, true},
+		{"Content-Length: 12\r\n", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := readContentLength(bufio.NewReader(strings.NewReader(tt.header)))
+		if got != tt.want || (err != nil) != tt.err {
+			t.Errorf("readContentLength(%q) = %d, %v, want %d, error %v", tt.header, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestLSPDiagnose(t *testing.T) {
+	body := strings.Repeat("\tn++\n", 10)
+	text := "package a\n\ntype T struct{}\n\nfunc (T) A() {}\n\nfunc (*T) B() {}\n\n" +
+		"func f(n int) {\n" + body + "}\n\nfunc g(n int) {\n" + body + "}\n\nfunc h(é bool) {\n\tvar _, _ = \"😀\", 0; if é {\n\t}\n}\n"
+	s := &lspServer{opts: lint.DefaultOptions()}
+	found := make(map[string][]lspRange)
+	for _, d := range s.diagnose(lspDocument{URI: "file:///src/a.go", Text: text}) {
+		found[d.Code] = append(found[d.Code], d.Range)
+	}
+	if n := len(found[lint.CheckDuplicate]); n != 2 {
+		t.Errorf("%d duplicate diagnostics, want 2", n)
+	}
+	if n := len(found[lint.CheckMixedReceivers]); n != 1 {
+		t.Errorf("%d mixed-receivers diagnostics, want 1", n)
+	}
+	want := []lspRange{{lspPosition{35, 21}, lspPosition{36, 2}}}
+	if got := found[lint.CheckEmptyIf]; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("empty-if diagnostics at %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/agflow/splint/lint"
+)
+
+var sortBy = flag.String("sort", "", "order the text and ndjson output by `key`: count, worst first, file or check")
+var groupBy = flag.String("group-by", "", "group the text output by `key`: file, check, package, or author with -blame")
+
+func byPosition(a, b *lint.Offender) bool {
+	if a.Position.Filename != b.Position.Filename {
+		return a.Position.Filename < b.Position.Filename
+	}
+	if a.Position.Line != b.Position.Line {
+		return a.Position.Line < b.Position.Line
+	}
+	return a.Position.Column < b.Position.Column
+}
+
+// sortKeys orders offenders for -sort.  The offenders start out in the
+// order of the checks, which sorting by check keeps.
+var sortKeys = map[string]func(a, b *lint.Offender) bool{
+	"count": func(a, b *lint.Offender) bool { return a.Count > b.Count },
+	"file":  byPosition,
+	"check": func(a, b *lint.Offender) bool { return false },
+}
+
+// groupKeys names the group of an offender for -group-by.
+var groupKeys = map[string]func(*lint.Offender) string{
+	"file":    func(o *lint.Offender) string { return o.Position.Filename },
+	"check":   func(o *lint.Offender) string { return o.Check },
+	"package": func(o *lint.Offender) string { return o.Package },
+	"author": func(o *lint.Offender) string {
+		if o.Blame == nil {
+			return "unknown author"
+		}
+		return o.Blame.Author
+	},
+}
+
+// checkOrder reports whether -sort and -group-by have valid keys.
+func checkOrder() error {
+	if _, ok := sortKeys[*sortBy]; *sortBy != "" && !ok {
+		return fmt.Errorf("unknown sort key %q", *sortBy)
+	}
+	if _, ok := groupKeys[*groupBy]; *groupBy != "" && !ok {
+		return fmt.Errorf("unknown group key %q", *groupBy)
+	}
+	if *sortBy != "" && *outputFormat != "text" && *outputFormat != "ndjson" {
+		return fmt.Errorf("-sort only works with text and ndjson output")
+	}
+	if *groupBy != "" && *outputFormat != "text" {
+		return fmt.Errorf("-group-by only works with text output")
+	}
+	if *groupBy == "author" && !*blameMode {
+		return fmt.Errorf("-group-by=author needs -blame")
+	}
+	if *blameMode && streamers[*outputFormat] != nil && *outputFormat != "text" {
+		return fmt.Errorf("-blame doesn't work with %s output", *outputFormat)
+	}
+	return nil
+}
+
+// ordered reports whether the text output waits for the end of the run, to
+// print the issues sorted or grouped, or blamed.
+func ordered() bool {
+	return *sortBy != "" || *groupBy != "" || *blameMode
+}
+
+// printOrdered prints every issue, sorted with -sort, and grouped with
+// -group-by.
+func printOrdered(w io.Writer, summary *lint.Summary) {
+	var offenders []*lint.Offender
+	for _, section := range summary.Sections() {
+		offenders = append(offenders, section.Offenders...)
+	}
+	if less, ok := sortKeys[*sortBy]; ok {
+		sort.SliceStable(offenders, func(i, j int) bool { return less(offenders[i], offenders[j]) })
+	}
+	key, ok := groupKeys[*groupBy]
+	if !ok {
+		for _, o := range offenders {
+			printText(w, "", o)
+		}
+		return
+	}
+	printGroups(w, offenders, key)
+}
+
+// printGroups prints offenders under a header for each group, in the order
+// of the groups' names.
+func printGroups(w io.Writer, offenders []*lint.Offender, key func(*lint.Offender) string) {
+	groups := make(map[string][]*lint.Offender)
+	var names []string
+	for _, o := range offenders {
+		name := key(o)
+		if _, seen := groups[name]; !seen {
+			names = append(names, name)
+		}
+		groups[name] = append(groups[name], o)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s (%d issues):\n", name, len(groups[name]))
+		for _, o := range groups[name] {
+			printText(w, "  ", o)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+// thresholdFlag is a threshold that can also be given as a percentile,
+// like "p95", which is computed over the functions of the run before
+// checking them.
+type thresholdFlag struct {
+	value      int
+	percentile float64
+}
+
+func thresholdVar(name string, value int, usage string) *thresholdFlag {
+	t := &thresholdFlag{value: value}
+	flag.Var(t, name, usage+", or a percentile like p95 of the functions analyzed")
+	return t
+}
+
+func (t *thresholdFlag) String() string {
+	if t.percentile > 0 {
+		return "p" + strconv.FormatFloat(t.percentile, 'g', -1, 64)
+	}
+	return strconv.Itoa(t.value)
+}
+
+func (t *thresholdFlag) Set(s string) error {
+	if p, ok := strings.CutPrefix(s, "p"); ok {
+		percentile, err := strconv.ParseFloat(p, 64)
+		if err != nil || percentile <= 0 || percentile > 100 {
+			return fmt.Errorf("invalid percentile %q", s)
+		}
+		t.percentile = percentile
+		return nil
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	t.value, t.percentile = value, 0
+	return nil
+}
+
+// on reports whether a threshold that is off at zero was turned on.
+func (t *thresholdFlag) on() bool {
+	return t.value > 0 || t.percentile > 0
+}
+
+// adaptiveThreshold ties a threshold flag to the function metric and the
+// option it sets.
+type adaptiveThreshold struct {
+	flag   *thresholdFlag
+	metric func(*lint.FunctionMetrics) int
+	set    func(*lint.Options, int)
+}
+
+var adaptiveThresholds = []adaptiveThreshold{
+	{statementThreshold, func(m *lint.FunctionMetrics) int { return m.Statements }, func(o *lint.Options, n int) { o.StatementThreshold = n }},
+	{lineThreshold, func(m *lint.FunctionMetrics) int { return m.Lines }, func(o *lint.Options, n int) { o.LineThreshold = n }},
+	{paramThreshold, func(m *lint.FunctionMetrics) int { return m.Params }, func(o *lint.Options, n int) { o.ParamThreshold = n }},
+	{resultThreshold, func(m *lint.FunctionMetrics) int { return m.Results }, func(o *lint.Options, n int) { o.ResultThreshold = n }},
+	{returnThreshold, func(m *lint.FunctionMetrics) int { return m.Returns }, func(o *lint.Options, n int) { o.ReturnThreshold = n }},
+	{localThreshold, func(m *lint.FunctionMetrics) int { return m.Locals }, func(o *lint.Options, n int) { o.LocalThreshold = n }},
+	{cognitiveThreshold, func(m *lint.FunctionMetrics) int { return m.Cognitive }, func(o *lint.Options, n int) { o.CognitiveThreshold = n }},
+}
+
+// resolvePercentiles replaces the thresholds given as percentiles by their
+// value over the functions found in args, which are analyzed once more
+// for it.
+func resolvePercentiles(args []string, opts lint.Options) (lint.Options, error) {
+	var todo []adaptiveThreshold
+	for _, a := range adaptiveThresholds {
+		if a.flag.percentile > 0 {
+			todo = append(todo, a)
+		}
+	}
+	if len(todo) == 0 {
+		return opts, nil
+	}
+	summary, err := analyze(args, opts.Measuring())
+	if summary == nil {
+		return opts, err
+	}
+	for _, a := range todo {
+		values := make([]int, len(summary.Functions))
+		for i, m := range summary.Functions {
+			values[i] = a.metric(m)
+		}
+		sort.Ints(values)
+		a.set(&opts, lint.Percentile(values, a.flag.percentile))
+	}
+	return opts, nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"plugin"
+)
+
+var plugins stringsFlag
+
+func init() {
+	flag.Var(&plugins, "plugin", "load custom checks from the go plugin `file` (repeatable)")
+}
+
+// loadPlugins opens the plugins given with -plugin.  A plugin adds its
+// checks by calling lint.Register from an init function, which runs as
+// the plugin is opened.
+func loadPlugins() error {
+	for _, path := range plugins {
+		if _, err := plugin.Open(path); err != nil {
+			return fmt.Errorf("error loading plugin %s: %s", path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+var profile = flag.String("profile", "", "start from the thresholds of a `profile`: "+strings.Join(lint.Profiles(), ", "))
+
+// profileFlags gives the values of the threshold flags in a profile.
+func profileFlags(p lint.Options) map[string]string {
+	itoa := strconv.Itoa
+	ftoa := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	return map[string]string{
+		"s":                    itoa(p.StatementThreshold),
+		"init":                 itoa(p.InitThreshold),
+		"lines":                itoa(p.LineThreshold),
+		"p":                    itoa(p.ParamThreshold),
+		"variadic":             itoa(p.VariadicThreshold),
+		"r":                    itoa(p.ResultThreshold),
+		"returns":              itoa(p.ReturnThreshold),
+		"locals":               itoa(p.LocalThreshold),
+		"c":                    itoa(p.IfChainThreshold),
+		"f":                    itoa(p.IfBodyThreshold),
+		"cases":                itoa(p.SwitchCaseThreshold),
+		"type-cases":           itoa(p.TypeSwitchCaseThreshold),
+		"case-body":            itoa(p.CaseBodyThreshold),
+		"labels":               itoa(p.LabelThreshold),
+		"cond":                 itoa(p.ConditionThreshold),
+		"guard":                itoa(p.GuardThreshold),
+		"chain":                itoa(p.ChainThreshold),
+		"goroutines":           itoa(p.GoroutineThreshold),
+		"fanout":               itoa(p.FanOutThreshold),
+		"fanin":                itoa(p.FanInThreshold),
+		"closure-depth":        itoa(p.ClosureDepthThreshold),
+		"discarded-errors":     itoa(p.DiscardedErrorThreshold),
+		"cog":                  itoa(p.CognitiveThreshold),
+		"dup":                  itoa(p.DuplicateThreshold),
+		"dup-similarity":       itoa(p.DuplicateSimilarity),
+		"file-lines":           itoa(p.FileLineThreshold),
+		"file-funcs":           itoa(p.FileFunctionThreshold),
+		"fields":               itoa(p.StructFieldThreshold),
+		"iface-methods":        itoa(p.InterfaceMethodThreshold),
+		"methods":              itoa(p.MethodThreshold),
+		"imports":              itoa(p.ImportThreshold),
+		"globals":              itoa(p.GlobalThreshold),
+		"magic":                strconv.FormatBool(p.MagicNumbers),
+		"magic-max":            itoa(p.MagicNumberThreshold),
+		"panics":               strconv.FormatBool(p.Panics),
+		"panics-max":           itoa(p.PanicThreshold),
+		"switch-default":       strconv.FormatBool(p.SwitchDefaults),
+		"switch-default-cases": itoa(p.SwitchDefaultThreshold),
+		"maxlen":               itoa(p.MaxLineLength),
+		"comments":             itoa(p.CommentThreshold),
+		"comment-ratio":        ftoa(p.CommentRatio),
+		"halstead-volume":      ftoa(p.HalsteadVolume),
+		"halstead-difficulty":  ftoa(p.HalsteadDifficulty),
+		"halstead-effort":      ftoa(p.HalsteadEffort),
+		"min-mi":               ftoa(p.MinMaintainability),
+		"todos":                itoa(p.MarkerThreshold),
+	}
+}
+
+// applyProfile sets the threshold flags that were not given on the command
+// line to the values of -profile.
+func applyProfile() error {
+	if *profile == "" {
+		return nil
+	}
+	p, err := lint.Profile(*profile)
+	if err != nil {
+		return err
+	}
+	given := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	for name, value := range profileFlags(p) {
+		if !given[name] {
+			if err := flag.Set(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/agflow/splint/lint"
+)
+
+var metricsAddr = flag.String("metrics-addr", "", "with -watch, serve Prometheus metrics of the latest results on `address`/metrics")
+
+// statementBuckets are the upper bounds of the buckets of the histogram
+// of function lengths.
+var statementBuckets = []int{5, 10, 20, 30, 50, 100}
+
+// writeMetrics writes a summary in the Prometheus text exposition format:
+// the issues of each check and the functions and statements of each
+// package as gauges, and the lengths of the functions as a histogram when
+// the summary has their metrics.
+func writeMetrics(w io.Writer, summary *lint.Summary) {
+	var packages []string
+	for path := range summary.Packages {
+		packages = append(packages, path)
+	}
+	sort.Strings(packages)
+
+	fmt.Fprintln(w, "# HELP splint_issues Issues found, by package and check.")
+	fmt.Fprintln(w, "# TYPE splint_issues gauge")
+	for _, path := range packages {
+		writeIssues(w, path, summary.Packages[path].Offenders)
+	}
+	fmt.Fprintln(w, "# HELP splint_functions Functions analyzed, by package.")
+	fmt.Fprintln(w, "# TYPE splint_functions gauge")
+	for _, path := range packages {
+		fmt.Fprintf(w, "splint_functions{package=%s} %d\n", labelValue(path), summary.Packages[path].Functions)
+	}
+	fmt.Fprintln(w, "# HELP splint_statements Statements in functions, by package.")
+	fmt.Fprintln(w, "# TYPE splint_statements gauge")
+	for _, path := range packages {
+		fmt.Fprintf(w, "splint_statements{package=%s} %d\n", labelValue(path), summary.Packages[path].Statements)
+	}
+	if len(summary.Functions) > 0 {
+		writeStatementHistogram(w, summary.Functions)
+	}
+}
+
+// labelEscaper escapes the characters the Prometheus text format does not
+// take as they are in label values.  Other characters, unlike in Go
+// strings, are not escaped.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue quotes a label value.
+func labelValue(s string) string {
+	return `"` + labelEscaper.Replace(s) + `"`
+}
+
+// writeIssues writes the issue counts of a package, by check.
+func writeIssues(w io.Writer, path string, offenders map[string]int) {
+	var checks []string
+	for check := range offenders {
+		checks = append(checks, check)
+	}
+	sort.Strings(checks)
+	for _, check := range checks {
+		fmt.Fprintf(w, "splint_issues{package=%s,check=%s} %d\n", labelValue(path), labelValue(check), offenders[check])
+	}
+}
+
+// writeStatementHistogram writes the histogram of the statement counts of
+// functions, whose buckets are cumulative.
+func writeStatementHistogram(w io.Writer, functions []*lint.FunctionMetrics) {
+	counts := make([]int, len(statementBuckets))
+	sum := 0
+	for _, m := range functions {
+		sum += m.Statements
+		for i, le := range statementBuckets {
+			if m.Statements <= le {
+				counts[i]++
+			}
+		}
+	}
+	fmt.Fprintln(w, "# HELP splint_function_statements Statement counts of functions.")
+	fmt.Fprintln(w, "# TYPE splint_function_statements histogram")
+	for i, le := range statementBuckets {
+		fmt.Fprintf(w, "splint_function_statements_bucket{le=\"%d\"} %d\n", le, counts[i])
+	}
+	fmt.Fprintf(w, "splint_function_statements_bucket{le=\"+Inf\"} %d\n", len(functions))
+	fmt.Fprintf(w, "splint_function_statements_sum %d\n", sum)
+	fmt.Fprintf(w, "splint_function_statements_count %d\n", len(functions))
+}
+
+// latest holds the latest summary, for the /metrics endpoint, which is
+// read while the next one is computed.
+type latest struct {
+	mu      sync.Mutex
+	summary *lint.Summary
+}
+
+func (l *latest) set(summary *lint.Summary) {
+	l.mu.Lock()
+	l.summary = summary
+	l.mu.Unlock()
+}
+
+func (l *latest) get() *lint.Summary {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.summary
+}
+
+// metrics serves the latest summary in the Prometheus text format, or
+// 503 before the first analysis.
+func (l *latest) metrics(w http.ResponseWriter, r *http.Request) {
+	summary := l.get()
+	if summary == nil {
+		http.Error(w, "nothing analyzed yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeMetrics(w, summary)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/agflow/splint/lint"
+)
+
+func TestLabelValue(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"example.com/a", `"example.com/a"`},
+		{`C:\src\a`, `"C:\\src\\a"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{"two\nlines", `"two\nlines"`},
+		{"tab\there", "\"tab\there\""},
+		{"héllo/😀", `"héllo/😀"`},
+	}
+	for _, tt := range tests {
+		if got := labelValue(tt.value); got != tt.want {
+			t.Errorf("labelValue(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	summary := &lint.Summary{Packages: map[string]*lint.PackageSummary{
+		"é/a": {Functions: 2, Statements: 7, Offenders: map[string]int{lint.CheckEmptyIf: 1}},
+	}}
+	var b strings.Builder
+	writeMetrics(&b, summary)
+	for _, want := range []string{
+		`splint_issues{package="é/a",check="` + lint.CheckEmptyIf + `"} 1`,
+		`splint_functions{package="é/a"} 2`,
+		`splint_statements{package="é/a"} 7`,
+	} {
+		if !strings.Contains(b.String(), want+"\n") {
+			t.Errorf("no %s in\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+// splitRef splits a repository URL like https://github.com/org/repo@v1.2.0
+// into the URL and the ref, which is empty when none is given.  An @ before
+// the last slash, like in git@github.com:org/repo, is part of the URL.
+func splitRef(repo string) (url, ref string) {
+	i := strings.LastIndex(repo, "@")
+	if i < 0 || i < strings.LastIndex(repo, "/") {
+		return repo, ""
+	}
+	return repo[:i], repo[i+1:]
+}
+
+// clone fetches the files of a single commit of a repository into the
+// current directory, which must be empty.  Fetching the ref rather than
+// cloning a branch works for commits and tags too.
+func clone(url, ref string) error {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	if _, err := git("init", "-q"); err != nil {
+		return err
+	}
+	if _, err := git("fetch", "-q", "--depth", "1", url, ref); err != nil {
+		return err
+	}
+	_, err := git("checkout", "-q", "FETCH_HEAD")
+	return err
+}
+
+// remote analyzes a remote git repository, checked out in a temporary
+// directory, returning whether splint should exit with a non-zero status.
+// Issues are reported with paths relative to the root of the repository.
+func remote(repo string, opts lint.Options, write func(io.Writer, *lint.Summary) error, fail []string) bool {
+	for _, name := range []*string{outputFile, baselineFile} {
+		if *name != "" {
+			*name, _ = filepath.Abs(*name)
+		}
+	}
+	dir, err := os.MkdirTemp("", "splint-remote-")
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err == nil {
+		err = os.Chdir(dir)
+	}
+	if err != nil {
+		fmt.Println(err)
+		return true
+	}
+	defer os.Chdir(wd)
+
+	url, ref := splitRef(repo)
+	if err := clone(url, ref); err != nil {
+		fmt.Println(err)
+		return true
+	}
+	args := []string{"./..."}
+	return run(args, runOptions(args, opts), write, fail)
+}
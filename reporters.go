@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/agflow/splint/lint"
+)
+
+// writerReporter renders the whole summary with one of the writers once
+// the run is done, ignoring the issues as they are found.
+type writerReporter struct {
+	w     io.Writer
+	write func(io.Writer, *lint.Summary) error
+}
+
+func (r *writerReporter) Report(*lint.Offender) {}
+
+func (r *writerReporter) Finish(summary *lint.Summary) error {
+	return r.write(r.w, summary)
+}
+
+// textReporter prints every issue with print as soon as it is found, if
+// set, then the rankings, metrics and summary asked for.
+type textReporter struct {
+	w     io.Writer
+	print func(*lint.Offender)
+	opts  lint.Options
+}
+
+func (r *textReporter) Report(o *lint.Offender) {
+	if r.print != nil {
+		r.print(o)
+	}
+}
+
+func (r *textReporter) Finish(summary *lint.Summary) error {
+	if *quiet {
+		if *outputSummary {
+			printSummary(r.w, summary)
+		}
+		return nil
+	}
+	if *topN > 0 {
+		printTop(r.w, summary, *topN)
+	} else if ordered() {
+		printOrdered(r.w, summary)
+	}
+	if *showAll {
+		printAll(r.w, summary, r.opts)
+	}
+	if summary.Stats != nil {
+		printStats(r.w, summary.Stats)
+	}
+	if *outputSummary {
+		printSummary(r.w, summary)
+	}
+	return nil
+}
+
+// newTextReporter prints every issue as soon as it is found, unless they
+// are ranked, sorted or grouped.
+func newTextReporter(w io.Writer, opts lint.Options) lint.Reporter {
+	r := &textReporter{w: w, opts: opts}
+	if *topN == 0 && !ordered() {
+		r.print = func(o *lint.Offender) { printText(w, "", o) }
+	}
+	return r
+}
+
+// ndjsonReporter prints every issue as a json object on a line of its own,
+// with its message, as soon as it is found.  Issues sorted with -sort, or
+// cut to the -top N with the highest counts, are held until the end of the
+// run instead.  The first error writing them is returned by Finish.  The
+// -sum summary goes to stderr, keeping the output valid ndjson.
+type ndjsonReporter struct {
+	enc  *json.Encoder
+	hold bool
+	held []*lint.Offender
+	err  error
+}
+
+func newNDJSONReporter(w io.Writer, _ lint.Options) lint.Reporter {
+	return &ndjsonReporter{enc: json.NewEncoder(w), hold: *sortBy != "" || *topN > 0}
+}
+
+func (r *ndjsonReporter) Report(o *lint.Offender) {
+	if r.hold {
+		r.held = append(r.held, o)
+		return
+	}
+	r.encode(o)
+}
+
+func (r *ndjsonReporter) encode(o *lint.Offender) {
+	if r.err == nil {
+		r.err = r.enc.Encode(struct {
+			*lint.Offender
+			Message string
+		}{o, o.Message()})
+	}
+}
+
+func (r *ndjsonReporter) Finish(summary *lint.Summary) error {
+	held := r.held
+	if *topN > 0 {
+		sort.SliceStable(held, func(i, j int) bool { return held[i].Count > held[j].Count })
+		held = held[:min(*topN, len(held))]
+	}
+	if less, ok := sortKeys[*sortBy]; ok {
+		sort.SliceStable(held, func(i, j int) bool { return less(held[i], held[j]) })
+	}
+	for _, o := range held {
+		r.encode(o)
+	}
+	if r.err == nil && *outputSummary {
+		printSummary(os.Stderr, summary)
+	}
+	return r.err
+}
+
+// newReporter returns the reporter for the output format: the writers wait
+// for the end of the run, and the streamers print every issue as soon as
+// it is found.  -q only asks for the summary.
+func newReporter(w io.Writer, opts lint.Options, write func(io.Writer, *lint.Summary) error) lint.Reporter {
+	switch {
+	case *quiet:
+		return &textReporter{w: w, opts: opts}
+	case write != nil:
+		return &writerReporter{w, write}
+	}
+	return streamers[*outputFormat](w, opts)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+var serveAddr = flag.String("addr", "localhost:8080", "address splint serve listens on, like :8080 for every interface")
+var serveRoot = flag.String("serve-root", "", "let splint serve analyze paths, within `dir` and relative to it (default only the contents of files)")
+
+// maxRequest is the largest request body splint serve accepts.
+const maxRequest = 32 << 20
+
+// analyzeRequest is the body of a POST /analyze request: either paths on
+// the server's filesystem below -serve-root, as on the command line, or
+// the contents of files by name.
+type analyzeRequest struct {
+	Paths []string
+	Files map[string]string
+}
+
+// server answers the requests of splint serve, with the options of the
+// command line, and keeps the summary of the last analysis.
+type server struct {
+	opts lint.Options
+	latest
+}
+
+// serve runs an HTTP server analyzing code on request, until it fails.
+// Function metrics are always collected, for the histogram of /metrics.
+func serve(opts lint.Options) error {
+	opts.Metrics = true
+	s := &server{opts: opts}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyze", only(http.MethodPost, s.analyze))
+	mux.HandleFunc("/summary", only(http.MethodGet, s.summary))
+	mux.HandleFunc("/metrics", only(http.MethodGet, s.metrics))
+	fmt.Fprintf(os.Stderr, "splint listening on %s\n", *serveAddr)
+	return http.ListenAndServe(*serveAddr, mux)
+}
+
+// only restricts a handler to one method.
+func only(method string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			w.Header().Set("Allow", method)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *server) analyze(w http.ResponseWriter, r *http.Request) {
+	var req analyzeRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequest)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("bad request: %s", err), http.StatusBadRequest)
+		return
+	}
+	if (len(req.Paths) == 0) == (len(req.Files) == 0) {
+		http.Error(w, "bad request: expected either Paths or Files to analyze", http.StatusBadRequest)
+		return
+	}
+	summary, err := s.run(req)
+	if summary == nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.set(summary)
+	writeSummary(w, summary, err)
+}
+
+// run analyzes the paths or files of a request.  Like lint.Run, it
+// returns the errors of the files that could not be analyzed alongside the
+// summary of the others.
+func (s *server) run(req analyzeRequest) (*lint.Summary, error) {
+	if len(req.Paths) > 0 {
+		paths, err := resolvePaths(*serveRoot, req.Paths)
+		if err != nil {
+			return nil, err
+		}
+		return analyze(paths, s.opts)
+	}
+	summary := &lint.Summary{}
+	var errs []error
+	names := make([]string, 0, len(req.Files))
+	for name := range req.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := lint.NewParser(name, summary, s.opts).ParseSource([]byte(req.Files[name])); err != nil {
+			errs = append(errs, fmt.Errorf("error parsing %s: %s", name, err))
+		}
+	}
+	summary.CheckMethods(s.opts)
+	summary.CheckDuplicates(s.opts)
+	return summary, errors.Join(errs...)
+}
+
+// resolvePaths turns the paths of a request, and patterns like "./...",
+// into paths below root, refusing those that lead out of it, through ".."
+// or a symbolic link.  Without a root, no path is accepted.
+func resolvePaths(root string, paths []string) ([]string, error) {
+	if root == "" {
+		return nil, errors.New("paths are only accepted with -serve-root")
+	}
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		root = real
+	}
+	resolved := make([]string, len(paths))
+	for i, p := range paths {
+		dir, pattern := p, ""
+		if p == "..." || strings.HasSuffix(p, "/...") {
+			dir, pattern = strings.TrimSuffix(p, "..."), "..."
+		}
+		full := realPath(filepath.Join(root, dir))
+		rel, err := filepath.Rel(root, full)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("%s is outside of -serve-root", p)
+		}
+		resolved[i] = filepath.Join(full, pattern)
+	}
+	return resolved, nil
+}
+
+// realPath resolves the symbolic links of the longest part of a path that
+// exists.
+func realPath(path string) string {
+	rest := ""
+	for {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(real, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+func (s *server) summary(w http.ResponseWriter, r *http.Request) {
+	last := s.get()
+	if last == nil {
+		http.Error(w, "nothing analyzed yet", http.StatusNotFound)
+		return
+	}
+	writeSummary(w, last, nil)
+}
+
+// writeSummary answers with a summary as json, with the errors of the
+// files that could not be analyzed in an Errors field.
+func writeSummary(w http.ResponseWriter, summary *lint.Summary, err error) {
+	resp := struct {
+		*lint.Summary
+		Errors string `json:",omitempty"`
+	}{Summary: summary}
+	if err != nil {
+		resp.Errors = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestResolvePaths(t *testing.T) {
+	root := t.TempDir()
+	if real, err := filepath.EvalSymlinks(root); err == nil {
+		root = real
+	}
+	outside := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		paths []string
+		want  []string
+	}{
+		{[]string{"./..."}, []string{filepath.Join(root, "...")}},
+		{[]string{"..."}, []string{filepath.Join(root, "...")}},
+		{[]string{"pkg/a.go", "pkg/..."}, []string{filepath.Join(root, "pkg", "a.go"), filepath.Join(root, "pkg", "...")}},
+		{[]string{"/pkg"}, []string{filepath.Join(root, "pkg")}},
+		{[]string{"pkg/../pkg"}, []string{filepath.Join(root, "pkg")}},
+		{[]string{"../..."}, nil},
+		{[]string{"pkg/../../etc/passwd"}, nil},
+		{[]string{"pkg", "link/..."}, nil},
+		{[]string{"link/a.go"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := resolvePaths(root, tt.paths)
+		if (err != nil) != (tt.want == nil) || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("resolvePaths(%q) = %q, %v, want %q", tt.paths, got, err, tt.want)
+		}
+	}
+	if _, err := resolvePaths("", []string{"./..."}); err == nil {
+		t.Error("resolvePaths without a root: no error")
+	}
+}
@@ -1,329 +1,626 @@
 // splint is a little Go application to analyze Go source files.  It finds any functions that are
 // too long or have too many parameters or results.
 //
-// find . -name "*.go" -exec splint {} \;
+// splint accepts go files, directories, and recursive patterns like ./...
+// splint ./...
 // By default, splint will inform you of any functions that are more than 30 statements long, have more than five parameters, or have more than five results.
 //
 // You can change these values with command line flags. -s sets the statement count threshold, -p sets the parameter count threshold, and -r sets the result count threshold.
 // Check for all functions with more than 50 statements, 10 parameters, 7 results:
 // splint -s=50 -p=10 -r=7 **/*.go
+//
+//splint:ignore global-vars,exit-outside-main the command line flags, and exiting on bad ones
 package main
 
 import (
 	"encoding/json"
 	"flag"
 	"fmt"
-	"go/ast"
-	"go/parser"
-	"go/token"
+	"go/build"
+	"io"
 	"os"
-	"path"
-)
-
-var statementThreshold = flag.Int("s", 30, "function statement count threshold")
-var paramThreshold = flag.Int("p", 5, "parameter list length threshold")
-var resultThreshold = flag.Int("r", 5, "result list length threshold")
-var ifChainThreshold = flag.Int("c", 2, "if/else chain length threshold")
-var ifBodyThreshold = flag.Int("f", 20, "if body statement count threshold")
-var skipBoolParamCheck = flag.Bool("b", false, "don't warn on bool function params")
-var outputJSON = flag.Bool("j", false, "output results as json")
-var ignoreTestFiles = flag.Bool("i", false, "ignore test files")
-var outputSummary = flag.Bool("sum", false, "output summary")
+	"runtime"
+	"sort"
+	"strings"
 
-// Parser parses go source files, looking for potentially complex
-// code.
-type Parser struct {
-	filename string
-	first    bool
-	summary  *Summary
-	fileset  *token.FileSet
-}
+	"github.com/agflow/splint/lint"
+)
 
-// Offender contains the file, function, position, and count of
-// a block of code that splint has recognized as an issue.
-type Offender struct {
-	Filename string
-	Function string
-	Count    int
-	Position token.Position
+var defaults = lint.DefaultOptions()
+
+var statementThreshold = thresholdVar("s", defaults.StatementThreshold, "function statement count threshold")
+var initThreshold = flag.Int("init", defaults.InitThreshold, "init function statement count threshold")
+var lineThreshold = thresholdVar("lines", defaults.LineThreshold, "function line count threshold (0 disables the check)")
+var skipStatementCheck = flag.Bool("skip-statements", false, "don't count statements, for measuring function length in lines with -lines (same as -disable=statement-count)")
+var paramThreshold = thresholdVar("p", defaults.ParamThreshold, "parameter list length threshold")
+var variadicThreshold = flag.Int("variadic", defaults.VariadicThreshold, "threshold of params besides a variadic one")
+var resultThreshold = thresholdVar("r", defaults.ResultThreshold, "result list length threshold")
+var returnThreshold = thresholdVar("returns", defaults.ReturnThreshold, "return statement count threshold")
+var localThreshold = thresholdVar("locals", defaults.LocalThreshold, "local variable count threshold")
+var ifChainThreshold = flag.Int("c", defaults.IfChainThreshold, "if/else chain length threshold")
+var ifBodyThreshold = flag.Int("f", defaults.IfBodyThreshold, "if body statement count threshold")
+var switchCaseThreshold = flag.Int("cases", defaults.SwitchCaseThreshold, "switch case count threshold")
+var typeSwitchCaseThreshold = flag.Int("type-cases", defaults.TypeSwitchCaseThreshold, "type switch case count threshold")
+var caseBodyThreshold = flag.Int("case-body", defaults.CaseBodyThreshold, "case body statement count threshold")
+var labelThreshold = flag.Int("labels", defaults.LabelThreshold, "goto, label and labeled break or continue count threshold")
+var conditionThreshold = flag.Int("cond", defaults.ConditionThreshold, "threshold of boolean operators, and of comparisons, in an if or for condition")
+var guardThreshold = flag.Int("guard", defaults.GuardThreshold, "percentage of the statements of a function inside a single if above which a guard clause is suggested")
+var chainThreshold = flag.Int("chain", defaults.ChainThreshold, "threshold of calls chained in an expression, like a.B().C()")
+var goroutineThreshold = flag.Int("goroutines", defaults.GoroutineThreshold, "go statement count threshold")
+var fanOutThreshold = flag.Int("fanout", defaults.FanOutThreshold, "threshold of distinct functions and methods a function calls")
+var fanInThreshold = flag.Int("fanin", defaults.FanInThreshold, "threshold of call sites of a function over the cognitive complexity threshold, with -packages")
+var closureDepthThreshold = flag.Int("closure-depth", defaults.ClosureDepthThreshold, "threshold of function literals a function literal is nested in, counting itself")
+var discardedErrorThreshold = flag.Int("discarded-errors", defaults.DiscardedErrorThreshold, "discarded error count threshold, with -packages")
+var cognitiveThreshold = thresholdVar("cog", defaults.CognitiveThreshold, "cognitive complexity threshold")
+var duplicateThreshold = flag.Int("dup", defaults.DuplicateThreshold, "statement count from which functions are compared for duplicates")
+var duplicateSimilarity = flag.Int("dup-similarity", defaults.DuplicateSimilarity, "percentage of statements functions share from which they are near duplicates, 100 for exact copies only")
+var fileLineThreshold = flag.Int("file-lines", defaults.FileLineThreshold, "file line count threshold (0 disables the check)")
+var fileFunctionThreshold = flag.Int("file-funcs", defaults.FileFunctionThreshold, "functions per file threshold (0 disables the check)")
+var structFieldThreshold = flag.Int("fields", defaults.StructFieldThreshold, "struct field count threshold")
+var interfaceMethodThreshold = flag.Int("iface-methods", defaults.InterfaceMethodThreshold, "interface method count threshold")
+var magicNumbers = flag.Bool("magic", false, "report functions using magic numbers, numeric literals other than 0 and 1 that are not named constants")
+var magicNumberThreshold = flag.Int("magic-max", 0, "magic number count threshold, with -magic")
+var panics = flag.Bool("panics", false, "report functions calling panic, outside of init and main functions and test files, or recover")
+var panicThreshold = flag.Int("panics-max", 0, "panic and recover call count threshold, with -panics")
+var switchDefaults = flag.Bool("switch-default", false, "report switches without a default clause")
+var switchDefaultThreshold = flag.Int("switch-default-cases", defaults.SwitchDefaultThreshold, "case count threshold of switches without a default clause, with -switch-default")
+var allowedNumbers stringsFlag
+
+func init() {
+	flag.Var(&allowedNumbers, "allow-number", "don't count `number` as a magic number (repeatable)")
 }
 
-func (o *Offender) warning(msg string) {
-	if *outputJSON {
-		return
+var maxLineLength = flag.Int("maxlen", 0, "report lines longer than `N` columns")
+var tabWidth = flag.Int("tabwidth", defaults.TabWidth, "number of columns of a tab, with -maxlen")
+var commentThreshold = flag.Int("comments", 0, "report functions with more than `N` statements and few comments")
+var commentRatio = flag.Float64("comment-ratio", defaults.CommentRatio, "comment lines per statement below which -comments reports a function")
+var halsteadVolume = flag.Float64("halstead-volume", 0, "Halstead volume threshold (0 disables it)")
+var halsteadDifficulty = flag.Float64("halstead-difficulty", 0, "Halstead difficulty threshold (0 disables it)")
+var halsteadEffort = flag.Float64("halstead-effort", 0, "Halstead effort threshold (0 disables it)")
+var minMaintainability = flag.Float64("min-mi", 0, "report functions and files with a maintainability index, from 0 to 100, below `N`")
+var outputMetrics = flag.Bool("metrics", false, "include the metrics of every function in the json output")
+var methodThreshold = flag.Int("methods", defaults.MethodThreshold, "methods per type threshold")
+var importThreshold = flag.Int("imports", defaults.ImportThreshold, "imports per file threshold")
+var globalThreshold = flag.Int("globals", defaults.GlobalThreshold, "package variables per file threshold")
+var markerThreshold = flag.Int("todos", 0, "report files with more than `N` TODO, FIXME, HACK or XXX markers")
+var skipBoolParamCheck = flag.Bool("b", false, "don't warn on bool function params (same as -disable=bool-param)")
+var outputJSON = flag.Bool("j", false, "output results as json (same as -format=json)")
+var snippetLines = flag.Int("snippet", 0, "quote the first and last `N` lines of the source of every issue")
+var jsonFiles = flag.Bool("json-files", false, "add the issues grouped by file, with their counts, to the json output")
+var outputFormat = flag.String("format", "text", "output format: text, json, ndjson, html, github, codequality, csv, tsv, tap, template")
+var outputFile = flag.String("o", "", "write output to `file` instead of stdout")
+var ignoreFuncs = flag.String("ignore-funcs", "", "skip the functions whose name, or Type.Method name, matches `regexp`")
+var onlyFuncs = flag.String("only-funcs", "", "only check the functions whose name, or Type.Method name, matches `regexp`")
+var ignoreTestFiles = flag.Bool("i", false, "ignore test files (deprecated: analyze them with their own thresholds with -test-threshold)")
+var outputSummary = flag.Bool("sum", false, "output summary")
+var quiet = flag.Bool("q", false, "print no issues, only the summary with -sum, and exit with status 1 if any was found")
+var failOn = flag.String("fail-on", "", "comma-separated `checks` that cause a non-zero exit status (default all checks with -sum, -q or -staged, none otherwise)")
+var noFail = flag.Bool("no-fail", false, "always exit with status 0 when the analysis ran")
+var jobs = flag.Int("jobs", runtime.GOMAXPROCS(0), "number of files to analyze concurrently")
+var packagesMode = flag.Bool("packages", false, "load whole packages with type information; arguments are package patterns")
+var includeGenerated = flag.Bool("include-generated", false, "check generated files too")
+var skipCgo = flag.Bool("skip-cgo", false, "skip the files that import \"C\"")
+var stdinFilename = flag.String("stdin-filename", "", "file `name` to report for source read from stdin with -")
+var baselineFile = flag.String("baseline", "", "don't report the issues recorded in baseline `file`")
+var targetOS = flag.String("goos", build.Default.GOOS, "analyze the files built for `os`")
+var targetArch = flag.String("goarch", build.Default.GOARCH, "analyze the files built for `arch`")
+var buildTags = flag.String("tags", "", "comma-separated build `tags` files are analyzed with")
+var allPlatforms = flag.Bool("all-platforms", false, "analyze every file, whatever its build constraints")
+var writeBaselineFile = flag.String("write-baseline", "", "record all issues found in baseline `file`")
+
+func options() lint.Options {
+	return lint.Options{
+		StatementThreshold:       statementThreshold.value,
+		InitThreshold:            *initThreshold,
+		LineThreshold:            lineThreshold.value,
+		ParamThreshold:           paramThreshold.value,
+		VariadicThreshold:        *variadicThreshold,
+		ResultThreshold:          resultThreshold.value,
+		ReturnThreshold:          returnThreshold.value,
+		LocalThreshold:           localThreshold.value,
+		IfChainThreshold:         *ifChainThreshold,
+		IfBodyThreshold:          *ifBodyThreshold,
+		SwitchCaseThreshold:      *switchCaseThreshold,
+		TypeSwitchCaseThreshold:  *typeSwitchCaseThreshold,
+		CaseBodyThreshold:        *caseBodyThreshold,
+		LabelThreshold:           *labelThreshold,
+		DiscardedErrorThreshold:  *discardedErrorThreshold,
+		ConditionThreshold:       *conditionThreshold,
+		GuardThreshold:           *guardThreshold,
+		ChainThreshold:           *chainThreshold,
+		GoroutineThreshold:       *goroutineThreshold,
+		FanOutThreshold:          *fanOutThreshold,
+		FanInThreshold:           *fanInThreshold,
+		ClosureDepthThreshold:    *closureDepthThreshold,
+		MagicNumbers:             *magicNumbers,
+		MagicNumberThreshold:     *magicNumberThreshold,
+		Panics:                   *panics,
+		PanicThreshold:           *panicThreshold,
+		SwitchDefaults:           *switchDefaults,
+		SwitchDefaultThreshold:   *switchDefaultThreshold,
+		AllowedNumbers:           allowedNumbers,
+		MaxLineLength:            *maxLineLength,
+		TabWidth:                 *tabWidth,
+		CommentThreshold:         *commentThreshold,
+		CommentRatio:             *commentRatio,
+		HalsteadVolume:           *halsteadVolume,
+		HalsteadDifficulty:       *halsteadDifficulty,
+		HalsteadEffort:           *halsteadEffort,
+		MinMaintainability:       *minMaintainability,
+		Metrics:                  *outputMetrics,
+		CognitiveThreshold:       cognitiveThreshold.value,
+		DuplicateThreshold:       *duplicateThreshold,
+		DuplicateSimilarity:      *duplicateSimilarity,
+		FileLineThreshold:        *fileLineThreshold,
+		StructFieldThreshold:     *structFieldThreshold,
+		InterfaceMethodThreshold: *interfaceMethodThreshold,
+		MethodThreshold:          *methodThreshold,
+		FileFunctionThreshold:    *fileFunctionThreshold,
+		ImportThreshold:          *importThreshold,
+		GlobalThreshold:          *globalThreshold,
+		MarkerThreshold:          *markerThreshold,
+		IgnoreTestFiles:          *ignoreTestFiles,
+		IncludeGenerated:         *includeGenerated,
+		SkipCgo:                  *skipCgo,
+		Jobs:                     *jobs,
+		SnippetLines:             *snippetLines,
+		StdinFilename:            *stdinFilename,
+		SkipDirs:                 commaList(*skipDirs),
 	}
-	fmt.Printf("%s:\tfunction %s %s: %d\n", o.Position, o.Function, msg, o.Count)
 }
 
-func (o *Offender) warnNoCount(msg string) {
-	if *outputJSON {
+// platform sets the platform and build tags files are analyzed for, unless
+// -all-platforms asks for every variant.
+func platform(opts *lint.Options) {
+	if *allPlatforms {
 		return
 	}
-	fmt.Printf("%s:\tfunction %s %s\n", o.Position, o.Function, msg)
+	opts.GOOS = *targetOS
+	opts.GOARCH = *targetArch
+	opts.BuildTags = commaList(*buildTags)
 }
 
-// Summary is a collection of Offenders for all the different
-// checks that splint performs.
-type Summary struct {
-	Statement  []*Offender
-	Param      []*Offender
-	Result     []*Offender
-	EmptyIfs   []*Offender
-	IfChains   []*Offender
-	BoolParams []*Offender
-	LongIfs    []*Offender
-
-	// redundant, but using these for easy json output
-	NumAboveStatementThreshold int
-	NumAboveParamThreshold     int
-	NumAboveResultThreshold    int
-	NumIfChains                int
-	NumEmptyIfs                int
-	NumWithBoolParams          int
-	NumLongIfs                 int
+// turnedOff reports whether a check is off, so that the summary leaves it
+// out rather than counting no issues.
+func turnedOff(check string) (off bool) {
+	switch check {
+	case lint.CheckLineCount:
+		off = !lineThreshold.on()
+	case lint.CheckMagicNumber:
+		off = !*magicNumbers
+	case lint.CheckPanics:
+		off = !*panics
+	case lint.CheckSwitchDefault:
+		off = !*switchDefaults
+	case lint.CheckDiscardedError, lint.CheckFanIn:
+		off = !*packagesMode
+	case lint.CheckLineLength:
+		off = *maxLineLength <= 0
+	case lint.CheckCommentDensity:
+		off = *commentThreshold <= 0
+	case lint.CheckMarkers:
+		off = *markerThreshold <= 0
+	case lint.CheckMaintainability:
+		off = *minMaintainability <= 0
+	case lint.CheckHalstead:
+		off = *halsteadVolume <= 0 && *halsteadDifficulty <= 0 && *halsteadEffort <= 0
+	}
+	return off || disabled[check]
 }
 
-// IsClean checks if there are some issues to be reported
-func (s *Summary) IsClean() bool {
-	base := len(s.Statement) == 0 && len(s.Param) == 0 && len(s.Result) == 0 && len(s.EmptyIfs) == 0 && len(s.IfChains) == 0 && len(s.LongIfs) == 0
-	if *skipBoolParamCheck {
-		return base
+func printSummary(w io.Writer, summary *lint.Summary) {
+	fmt.Fprintln(w)
+	for _, section := range summary.Sections() {
+		if !turnedOff(section.Check) {
+			fmt.Fprintf(w, "Number of %s: %d\n", strings.ToLower(section.Name[:1])+section.Name[1:], len(section.Offenders))
+		}
+	}
+	fmt.Fprintln(w, "Number of suppressed issues:", summary.NumSuppressed)
+	if summary.NumOverrides > 0 {
+		fmt.Fprintln(w, "Number of threshold overrides:", summary.NumOverrides)
+	}
+	if *baselineFile != "" {
+		fmt.Fprintln(w, "Number of issues in baseline:", summary.NumBaselined)
 	}
-	return base && len(s.BoolParams) == 0
+	if summary.NumExcluded > 0 {
+		fmt.Fprintln(w, "Number of excluded files:", summary.NumExcluded)
+	}
+	printSkipped(w, summary)
+	if *diffRef != "" {
+		fmt.Fprintln(w, "Number of issues in unchanged functions:", summary.NumUnchanged)
+	}
+	printTests(w, summary)
+	printPackages(w, summary)
+	printMarkers(w, summary)
 }
 
-func (s *Summary) addStatement(o *Offender) {
-	s.Statement = append(s.Statement, o)
-	s.NumAboveStatementThreshold++
-	o.warning("too long")
+// printTests prints the number of issues of each check found in test
+// files, which the other numbers include.
+func printTests(w io.Writer, summary *lint.Summary) {
+	if len(summary.Tests) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nIssues in test files:")
+	for _, check := range lint.Checks() {
+		if n := summary.Tests[check]; n > 0 {
+			fmt.Fprintf(w, "%s: %d\n", check, n)
+		}
+	}
 }
 
-func (s *Summary) addParam(o *Offender) {
-	s.Param = append(s.Param, o)
-	s.NumAboveParamThreshold++
-	o.warning("too many params")
+// printSkipped prints the number of directories skipped by name, and of
+// files skipped for being generated, using cgo or built for another
+// platform.
+func printSkipped(w io.Writer, summary *lint.Summary) {
+	var names []string
+	for name := range summary.Skipped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "Number of %s directories skipped: %d\n", name, summary.Skipped[name])
+	}
+	if summary.NumGenerated > 0 {
+		fmt.Fprintln(w, "Number of generated files skipped:", summary.NumGenerated)
+	}
+	if summary.NumCgo > 0 {
+		fmt.Fprintln(w, "Number of cgo files skipped:", summary.NumCgo)
+	}
+	if summary.NumConstrained > 0 {
+		fmt.Fprintln(w, "Number of files skipped by build constraints:", summary.NumConstrained)
+	}
 }
 
-func (s *Summary) addBoolParam(o *Offender) {
-	s.BoolParams = append(s.BoolParams, o)
-	s.NumWithBoolParams++
-	o.warnNoCount("bool function param")
+// printMarkers prints the number of TODO markers of each file, most first.
+func printMarkers(w io.Writer, summary *lint.Summary) {
+	if summary.NumMarkers == 0 {
+		return
+	}
+	var files []string
+	for filename := range summary.Markers {
+		files = append(files, filename)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		if summary.Markers[files[i]] != summary.Markers[files[j]] {
+			return summary.Markers[files[i]] > summary.Markers[files[j]]
+		}
+		return files[i] < files[j]
+	})
+	fmt.Fprintf(w, "\nTODO, FIXME, HACK and XXX markers: %d\n", summary.NumMarkers)
+	for _, filename := range files {
+		fmt.Fprintf(w, "%s: %d\n", filename, summary.Markers[filename])
+	}
 }
 
-func (s *Summary) addResult(o *Offender) {
-	s.Result = append(s.Result, o)
-	s.NumAboveResultThreshold++
-	o.warning("too many results")
+// printPackages prints the rollup of each package, with its issues
+// counted by check.
+func printPackages(w io.Writer, summary *lint.Summary) {
+	var paths []string
+	for path := range summary.Packages {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Packages:")
+	for _, path := range paths {
+		p := summary.Packages[path]
+		var issues []string
+		total := 0
+		for _, check := range lint.Checks() {
+			if n := p.Offenders[check]; n > 0 {
+				issues = append(issues, fmt.Sprintf("%s=%d", check, n))
+				total += n
+			}
+		}
+		fmt.Fprintf(w, "%s: %d functions, %.1f avg statements, %d max statements",
+			path, p.Functions, p.AvgStatements, p.MaxStatements)
+		if p.Inits > 0 {
+			fmt.Fprintf(w, ", %d init functions", p.Inits)
+		}
+		fmt.Fprintf(w, ", %d issues", total)
+		if len(issues) > 0 {
+			fmt.Fprintf(w, " (%s)", strings.Join(issues, ", "))
+		}
+		fmt.Fprintln(w)
+	}
 }
 
-func (s *Summary) addEmptyIfBody(o *Offender) {
-	s.EmptyIfs = append(s.EmptyIfs, o)
-	s.NumEmptyIfs++
-	o.warnNoCount("if with empty body")
+func writeJSON(w io.Writer, summary *lint.Summary) error {
+	data, err := json.MarshalIndent(summary, "", "\t")
+	if err != nil {
+		return fmt.Errorf("json encode error: %s", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
 }
 
-func (s *Summary) addLongIfBody(o *Offender) {
-	s.LongIfs = append(s.LongIfs, o)
-	s.NumLongIfs++
-	o.warnNoCount("if with long body")
+// writers are the output formats that render the whole summary at the end
+// of the run, as opposed to text output which is printed as offenders are
+// found.
+var writers = map[string]func(io.Writer, *lint.Summary) error{
+	"json":        writeJSON,
+	"html":        writeHTML,
+	"github":      writeGitHub,
+	"codequality": writeCodeQuality,
+	"csv":         csvWriter(','),
+	"tsv":         csvWriter('\t'),
+	"tap":         writeTAP,
+	"template":    writeTemplate,
 }
 
-func (s *Summary) addIfChain(o *Offender) {
-	s.IfChains = append(s.IfChains, o)
-	s.NumIfChains++
-	o.warning("long if/else chain")
+func output() (io.WriteCloser, error) {
+	if *outputFile == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(*outputFile)
 }
 
-// NewParser creates a splint parser for a file.
-func NewParser(filename string, summary *Summary) *Parser {
-	return &Parser{filename: filename, first: true, summary: summary}
+func usage() {
+	fmt.Println("Usage: splint [options] <go file|dir|dir/...|->...")
+	fmt.Println("       splint [options] lsp")
+	fmt.Println("       splint [options] serve")
+	fmt.Println("       splint [options] compare <old.json> <new.json>")
+	fmt.Println("       splint history <splint.db> [function]")
+	fmt.Println("       splint [options] badge <go file|dir|dir/...>...")
+	fmt.Println("       splint [options] remote <repository url>[@ref]")
+	flag.PrintDefaults()
+	os.Exit(1)
 }
 
-func statementCount(n ast.Node) int {
-	total := 0
-	counter := func(node ast.Node) bool {
-		switch node.(type) {
-		case ast.Stmt:
-			total++
+// failChecks returns the checks whose issues should make splint exit with
+// a non-zero status.
+func failChecks() ([]string, error) {
+	if *noFail {
+		return nil, nil
+	}
+	if *failOn == "" {
+		if *outputSummary || *quiet || *staged {
+			return lint.Checks(), nil
 		}
-		return true
+		return nil, nil
 	}
-	ast.Inspect(n, counter)
-	return total
+	return lint.LookupChecks(strings.Split(*failOn, ","))
 }
 
-func (p *Parser) offender(function string, count int, pos token.Pos) *Offender {
-	return &Offender{
-		Filename: p.filename,
-		Function: function,
-		Count:    count,
-		Position: p.fileset.Position(pos),
+// setup completes the options given by flags with the config file, the
+// changed lines for -diff, the staged contents for -staged, and the
+// cache.
+func setup(opts *lint.Options) error {
+	if err := readConfig(opts); err != nil {
+		return err
+	}
+	if *staged {
+		opts.ReadFile = readStaged
 	}
+	platform(opts)
+	return openCache(opts)
 }
 
-func (p *Parser) checkFuncLength(x *ast.FuncDecl) {
-	numStatements := statementCount(x)
-	if numStatements <= *statementThreshold {
-		return
+func readConfig(opts *lint.Options) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
-
-	p.summary.addStatement(p.offender(x.Name.String(), numStatements, x.Pos()))
+	if *diffRef != "" {
+		if opts.Changes, err = readChanges(); err != nil {
+			return err
+		}
+	}
+	return cfg.apply(opts)
 }
 
-func (p *Parser) checkParamCount(x *ast.FuncDecl) {
-	numFields := x.Type.Params.NumFields()
-	if numFields <= *paramThreshold {
-		return
+// analyze runs the checks on files, or on packages with -packages.
+func analyze(args []string, opts lint.Options) (*lint.Summary, error) {
+	if *packagesMode {
+		return lint.RunPackages(args, opts)
 	}
+	return lint.Run(args, opts)
+}
 
-	p.summary.addParam(p.offender(x.Name.String(), numFields, x.Pos()))
+func readBaseline() *lint.Baseline {
+	b, err := lint.ReadBaseline(*baselineFile)
+	if err != nil {
+		fmt.Printf("error reading baseline %s: %s\n", *baselineFile, err)
+		os.Exit(1)
+	}
+	return b
 }
 
-func (p *Parser) checkBoolParams(x *ast.FuncDecl) {
-	if *skipBoolParamCheck {
-		return
+// writeBaseline records every issue found in args, ignoring any existing
+// baseline, so that later runs only report new issues.
+func writeBaseline(args []string, opts lint.Options) {
+	opts.Baseline = nil
+	summary, err := analyze(args, opts)
+	if summary == nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-	for _, f := range x.Type.Params.List {
-		// this is ugly, but:
-		if fmt.Sprintf("%s", f.Type) != "bool" {
-			continue
-		}
-		p.summary.addBoolParam(p.offender(x.Name.String(), 0, x.Pos()))
+	if err != nil {
+		fmt.Println(err)
 	}
+	b := lint.NewBaseline(summary)
+	if err := b.Write(*writeBaselineFile); err != nil {
+		fmt.Printf("error writing baseline %s: %s\n", *writeBaselineFile, err)
+		os.Exit(1)
+	}
+	fmt.Printf("wrote %d issues to %s\n", len(b.Issues), *writeBaselineFile)
 }
 
-func (p *Parser) checkResultCount(x *ast.FuncDecl) {
-	numResults := x.Type.Results.NumFields()
-	if numResults <= *resultThreshold {
-		return
+// checkFlags applies -profile and checks the flags that depend on
+// others, returning the checks that cause a non-zero exit status.
+func checkFlags() ([]string, error) {
+	if err := applyProfile(); err != nil {
+		return nil, err
 	}
-
-	p.summary.addResult(p.offender(x.Name.String(), numResults, x.Pos()))
+	if err := checkOrder(); err != nil {
+		return nil, err
+	}
+	return failChecks()
 }
 
-func (p *Parser) checkEmptyIfs(x *ast.FuncDecl) {
-	findIf := func(node ast.Node) bool {
-		switch y := node.(type) {
-		case *ast.IfStmt:
-			if y.Body == nil || len(y.Body.List) == 0 {
-				p.summary.addEmptyIfBody(p.offender(x.Name.String(), 0, y.Pos()))
-			} else if statementCount(y.Body) > *ifBodyThreshold {
-				p.summary.addLongIfBody(p.offender(x.Name.String(), 0, y.Pos()))
-			}
-		}
-		return true
+// parseFlags parses and checks the command line, returning the files to
+// analyze, the writer for the output format (nil for text output), and the
+// checks that cause a non-zero exit status.
+func parseFlags() ([]string, func(io.Writer, *lint.Summary) error, []string) {
+	flag.Parse()
+	args := stagedArgs(flag.Args())
+	if err := loadPlugins(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if *outputJSON {
+		*outputFormat = "json"
+	}
+	write, ok := writers[*outputFormat]
+	if len(args) == 0 || (!ok && streamers[*outputFormat] == nil) {
+		usage()
+	}
+	if *watchMode && (write != nil || *packagesMode) {
+		fmt.Println("-watch only works with text output, without -packages")
+		os.Exit(1)
+	}
+	fail, err := checkFlags()
+	if err != nil {
+		fmt.Println(err)
+		usage()
 	}
-	ast.Inspect(x, findIf)
+	return args, write, fail
 }
 
-func chainLength(x *ast.IfStmt) int {
-	if x.Else == nil {
-		return 0
-	}
-	if ifst, ok := x.Else.(*ast.IfStmt); ok {
-		return 1 + chainLength(ifst)
+// outputOptions sets up the options for the output asked for: metrics are
+// collected for -top, -stats, -all and -history, and the issues go to the
+// reporter as they are found.
+func outputOptions(opts lint.Options, reporter lint.Reporter) lint.Options {
+	if *topN > 0 || *showStats || *showAll || *historyFile != "" {
+		opts.Metrics = true
 	}
-	return 1
+	opts.Reporter = reporter
+	return opts
 }
 
-func (p *Parser) checkIfChains(x *ast.FuncDecl) {
-	findIf := func(node ast.Node) bool {
-		switch y := node.(type) {
-		case *ast.IfStmt:
-			n := chainLength(y)
-			if n > *ifChainThreshold {
-				p.summary.addIfChain(p.offender(x.Name.String(), n, y.Pos()))
-			}
-			return false // don't go any deeper
-		}
+// run analyzes args and writes the results, returning whether splint
+// should exit with a non-zero status.
+func run(args []string, opts lint.Options, write func(io.Writer, *lint.Summary) error, fail []string) bool {
+	out, err := output()
+	if err != nil {
+		fmt.Println(err)
 		return true
 	}
-	ast.Inspect(x, findIf)
-}
+	defer out.Close()
 
-func (p *Parser) examineFunc(x *ast.FuncDecl) {
-	p.checkFuncLength(x)
-	p.checkParamCount(x)
-	p.checkBoolParams(x)
-	p.checkResultCount(x)
-	p.checkEmptyIfs(x)
-	p.checkIfChains(x)
+	reporter := newReporter(out, opts, write)
+	summary, err := analyze(args, outputOptions(opts, reporter))
+	printDebug(opts)
+	if summary == nil {
+		fmt.Println(err)
+		return true
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+	historyErr := complete(summary)
+	if historyErr != nil {
+		fmt.Println(historyErr)
+	}
+	if err := reporter.Finish(summary); err != nil {
+		fmt.Println(err)
+	}
+	return summary.HasErrors(fail...) || historyErr != nil
 }
 
-func (p *Parser) examineDecls(tree *ast.File) {
-	for _, v := range tree.Decls {
-		switch x := v.(type) {
-		case *ast.FuncDecl:
-			p.examineFunc(x)
+// complete adds the statistics, file breakdown and blame asked for to a
+// summary, and records it in the history database, returning the error
+// recording it, which fails the run.
+func complete(summary *lint.Summary) error {
+	if *showStats {
+		summary.Stats = lint.NewStats(summary)
+	}
+	if *jsonFiles {
+		summary.Files = lint.NewFileSummaries(summary)
+	}
+	if *blameMode {
+		if err := blameAll(summary); err != nil {
+			fmt.Println(err)
 		}
 	}
+	if *historyFile != "" {
+		return recordHistory(*historyFile, summary)
+	}
+	return nil
 }
 
-// Parse parses a file, looking for issues in functions.
-func (p *Parser) Parse() {
-	p.fileset = token.NewFileSet()
-	tree, err := parser.ParseFile(p.fileset, p.filename, nil, 0)
+// runOptions adds the baseline to the options, and resolves the
+// thresholds given as percentiles.
+func runOptions(args []string, opts lint.Options) lint.Options {
+	if *baselineFile != "" {
+		opts.Baseline = readBaseline()
+	}
+	opts, err := resolvePercentiles(args, opts)
 	if err != nil {
-		fmt.Printf("error parsing %s: %s\n", p.filename, err)
-		return
+		fmt.Println(err)
+		os.Exit(1)
 	}
-
-	p.examineDecls(tree)
+	return opts
 }
 
-func isTestFile(filename string) bool {
-	base := path.Base(filename)
-	match, err := path.Match("*_test.go", base)
-	if err != nil {
-		fmt.Println("match error:", err)
-		return false
+// subcommand runs splint lsp, serve, compare, history, badge or remote,
+// reporting whether args asked for one, and whether it failed.
+func subcommand(args []string, opts lint.Options, write func(io.Writer, *lint.Summary) error, fail []string) (failed, ok bool) {
+	switch {
+	case len(args) == 1 && args[0] == "lsp":
+		err := serveLSP(opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		return err != nil, true
+	case len(args) == 3 && args[0] == "compare":
+		return compare(os.Stdout, args[1], args[2]), true
+	case len(args) == 1 && args[0] == "serve":
+		err := serve(opts)
+		fmt.Fprintln(os.Stderr, err)
+		return true, true
+	case len(args) == 2 && args[0] == "remote":
+		return remote(args[1], opts, write, fail), true
+	case len(args) > 1 && args[0] == "badge":
+		return badge(args[1:], runOptions(args[1:], opts)), true
+	case (len(args) == 2 || len(args) == 3) && args[0] == "history":
+		return history(os.Stdout, args[1], strings.Join(args[2:], "")), true
 	}
-	return match
+	return false, false
 }
 
-func parseFile(filename string, summary *Summary) {
-	if *ignoreTestFiles && isTestFile(filename) {
-		return
+// runMode runs splint in the mode asked for, returning whether it should
+// exit with a non-zero status.
+func runMode(args []string, opts lint.Options, write func(io.Writer, *lint.Summary) error, fail []string) bool {
+	switch {
+	case *writeBaselineFile != "":
+		writeBaseline(args, opts)
+		return false
+	case *watchMode:
+		fmt.Println(watch(args, opts))
+		return true
+	case *fixMode || *fixDryRun:
+		return fix(os.Stdout, args, opts)
 	}
-	parser := NewParser(filename, summary)
-	parser.Parse()
+	return run(args, opts, write, fail)
 }
 
 func main() {
-	flag.Parse()
-	args := flag.Args()
-	if len(args) == 0 {
-		fmt.Println("Usage: splint [options] <go file>...")
-		flag.PrintDefaults()
-		os.Exit(1)
-	}
-
-	summary := new(Summary)
+	args, write, fail := parseFlags()
 
-	for _, v := range args {
-		parseFile(v, summary)
+	opts := options()
+	if err := setup(&opts); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
-
-	if *outputJSON {
-		data, err := json.MarshalIndent(summary, "", "\t")
-		if err != nil {
-			fmt.Println("json encode error:", err)
-		}
-		fmt.Println(string(data))
-
-	} else if *outputSummary {
-		fmt.Println()
-		fmt.Println("Number of functions above statement threshold:", summary.NumAboveStatementThreshold)
-		fmt.Println("Number of functions above param threshold:", summary.NumAboveParamThreshold)
-		fmt.Println("Number of functions above result threshold:", summary.NumAboveResultThreshold)
-		fmt.Println("Number of long if/else chains:", summary.NumIfChains)
-		fmt.Println("Number of empty if bodies:", summary.NumEmptyIfs)
-		fmt.Println("Number of long if bodies:", summary.NumLongIfs)
-		if !*skipBoolParamCheck {
-			fmt.Println("Number of functions with bool params:", summary.NumWithBoolParams)
-		}
-		if !summary.IsClean() {
+	if failed, ok := subcommand(args, opts, write, fail); ok {
+		if failed {
 			os.Exit(1)
 		}
+		return
+	}
+	if runMode(args, runOptions(args, opts), write, fail) {
+		os.Exit(1)
 	}
 }
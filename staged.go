@@ -0,0 +1,55 @@
+//splint:ignore exit-outside-main exiting on bad input, like the flags
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var staged = flag.Bool("staged", false, "analyze the contents staged in git of the staged go files, and exit with status 1 on any issue, for pre-commit hooks")
+
+// stagedFiles returns the go files added, copied, modified or renamed in
+// the git index, below the working directory and relative to it.
+func stagedFiles() ([]string, error) {
+	out, err := git("diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMR", "-z", "--", "*.go")
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, name := range strings.Split(out, "\x00") {
+		if name != "" {
+			files = append(files, filepath.FromSlash(name))
+		}
+	}
+	return files, nil
+}
+
+// readStaged reads the contents of a file staged in the git index.
+func readStaged(filename string) ([]byte, error) {
+	out, err := git("show", ":./"+filepath.ToSlash(filename))
+	return []byte(out), err
+}
+
+// stagedArgs replaces the arguments with the staged go files for -staged,
+// exiting right away when there are none.
+func stagedArgs(args []string) []string {
+	if !*staged {
+		return args
+	}
+	if len(args) > 0 || *packagesMode || *watchMode {
+		fmt.Println("-staged takes no arguments, and doesn't work with -packages or -watch")
+		os.Exit(1)
+	}
+	files, err := stagedFiles()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(files) == 0 {
+		os.Exit(0)
+	}
+	return files
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agflow/splint/lint"
+)
+
+var showStats = flag.Bool("stats", false, "print the percentiles and histograms of function length, params and nesting")
+
+// histogramWidth is the width of the largest bar of a histogram.
+const histogramWidth = 40
+
+// printStats prints the percentiles of each distribution, and its
+// histogram as bars scaled to the largest bucket.
+func printStats(w io.Writer, stats []*lint.Distribution) {
+	for _, d := range stats {
+		fmt.Fprintf(w, "Function %s: p50 %d, p90 %d, p99 %d, max %d\n", d.Metric, d.P50, d.P90, d.P99, d.Max)
+		largest := 0
+		for _, b := range d.Histogram {
+			largest = max(largest, b.Count)
+		}
+		for _, b := range d.Histogram {
+			bucket := fmt.Sprint(b.From)
+			if b.To > b.From {
+				bucket = fmt.Sprintf("%d-%d", b.From, b.To)
+			}
+			bar := strings.Repeat("#", (b.Count*histogramWidth+largest-1)/largest)
+			fmt.Fprintf(w, "%10s\t%6d\t%s\n", bucket, b.Count, bar)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/agflow/splint/lint"
+)
+
+var topN = flag.Int("top", 0, "instead of listing every issue, rank the `N` worst functions by each metric")
+
+// topMetric is a function metric that functions can be ranked by.
+type topMetric struct {
+	title string
+	value func(*lint.FunctionMetrics) int
+}
+
+var topMetrics = []topMetric{
+	{"Longest functions (statements)", func(m *lint.FunctionMetrics) int { return m.Statements }},
+	{"Longest functions (lines)", func(m *lint.FunctionMetrics) int { return m.Lines }},
+	{"Most parameters", func(m *lint.FunctionMetrics) int { return m.Params }},
+	{"Most results", func(m *lint.FunctionMetrics) int { return m.Results }},
+	{"Most return statements", func(m *lint.FunctionMetrics) int { return m.Returns }},
+	{"Most local variables", func(m *lint.FunctionMetrics) int { return m.Locals }},
+	{"Longest if/else chains", func(m *lint.FunctionMetrics) int { return m.IfChain }},
+	{"Highest cognitive complexity", func(m *lint.FunctionMetrics) int { return m.Cognitive }},
+}
+
+// printTop prints the n worst functions by each metric.  Functions where
+// a metric is zero are never among the worst.
+func printTop(w io.Writer, summary *lint.Summary, n int) {
+	for _, metric := range topMetrics {
+		ranked := make([]*lint.FunctionMetrics, 0, len(summary.Functions))
+		for _, m := range summary.Functions {
+			if metric.value(m) > 0 {
+				ranked = append(ranked, m)
+			}
+		}
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return metric.value(ranked[i]) > metric.value(ranked[j])
+		})
+		if len(ranked) > n {
+			ranked = ranked[:n]
+		}
+
+		fmt.Fprintf(w, "%s:\n", metric.title)
+		for _, m := range ranked {
+			fmt.Fprintf(w, "%6d\t%s:\t%s\n", metric.value(m), m.Position, m.Function)
+		}
+		fmt.Fprintln(w)
+	}
+}
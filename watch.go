@@ -0,0 +1,188 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/agflow/splint/lint"
+)
+
+var watchMode = flag.Bool("watch", false, "keep running, re-analyzing files as they change")
+
+// settle is how long the watcher waits for more changes before analyzing,
+// since editors tend to touch a file several times when saving it.
+const settle = 200 * time.Millisecond
+
+// watcher keeps the results of every analyzed file, so that only the
+// files that change need to be analyzed again.
+type watcher struct {
+	args     []string
+	opts     lint.Options
+	baseline *lint.Baseline
+	results  map[string]*lint.Summary
+	fsw      *fsnotify.Watcher
+	latest
+}
+
+// watch analyzes args, then re-analyzes changed files and prints an
+// updated summary until it is interrupted.
+func watch(args []string, opts lint.Options) error {
+	for _, arg := range args {
+		if arg == "-" {
+			return errors.New("can't watch stdin")
+		}
+	}
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fsw.Close()
+
+	w := &watcher{args: args, opts: opts, baseline: opts.Baseline, results: make(map[string]*lint.Summary), fsw: fsw}
+	w.opts.Baseline = nil
+	w.opts.Warn = nil
+	w.opts.Reporter = nil
+	if *metricsAddr != "" {
+		w.opts.Metrics = true
+		go w.serveMetrics()
+	}
+	if err := w.addDirs(); err != nil {
+		return err
+	}
+	files, _, err := lint.Expand(args, w.opts)
+	if err != nil {
+		fmt.Println(err)
+	}
+	w.refresh(files)
+	return w.loop()
+}
+
+// serveMetrics serves the metrics of the latest results on -metrics-addr.
+func (w *watcher) serveMetrics() {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", w.metrics)
+	fmt.Println("metrics error:", http.ListenAndServe(*metricsAddr, mux))
+}
+
+// addDirs watches the directories holding the files to analyze.
+func (w *watcher) addDirs() error {
+	dirs, err := lint.WatchDirs(w.args, w.opts)
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := w.fsw.Add(dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loop re-analyzes the go files that change, once the changes settle,
+// until the watcher is closed.
+func (w *watcher) loop() error {
+	changed := make(map[string]bool)
+	timer := time.NewTimer(settle)
+	timer.Stop()
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if w.handle(ev, changed) {
+				timer.Reset(settle)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Println("watch error:", err)
+		case <-timer.C:
+			w.refresh(slices.Collect(maps.Keys(changed)))
+			clear(changed)
+		}
+	}
+}
+
+// handle watches the directories created, and records the go files
+// changed, reporting whether ev changed one.
+func (w *watcher) handle(ev fsnotify.Event, changed map[string]bool) bool {
+	if ev.Has(fsnotify.Create) {
+		w.watchCreated(ev.Name)
+	}
+	if filepath.Ext(ev.Name) != ".go" {
+		return false
+	}
+	changed[filepath.Clean(ev.Name)] = true
+	return true
+}
+
+// watchCreated watches a new directory, unless it is skipped.
+func (w *watcher) watchCreated(name string) {
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() && !w.opts.SkipsDir(info.Name()) {
+		w.fsw.Add(name)
+	}
+}
+
+// refresh re-analyzes the named files, forgets the ones that are no longer
+// part of the run, and prints the updated results.
+func (w *watcher) refresh(names []string) {
+	files, _, err := lint.Expand(w.args, w.opts)
+	if err != nil {
+		fmt.Println(err)
+	}
+	current := make(map[string]bool)
+	for _, f := range files {
+		current[filepath.Clean(f)] = true
+	}
+	for _, name := range names {
+		name = filepath.Clean(name)
+		if !current[name] {
+			delete(w.results, name)
+			continue
+		}
+		summary, err := lint.Run([]string{name}, w.opts)
+		if err != nil {
+			fmt.Println(err)
+		}
+		w.results[name] = summary
+	}
+	w.print()
+}
+
+func (w *watcher) print() {
+	var names []string
+	for name := range w.results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\n--- %s: %d files\n", time.Now().Format("15:04:05"), len(names))
+	total := &lint.Summary{Warn: func(o *lint.Offender) { fmt.Println(o) }}
+	var baseline *lint.Baseline
+	if w.baseline != nil {
+		// a fresh copy, as matching offenders uses up baseline entries
+		baseline = &lint.Baseline{Issues: w.baseline.Issues}
+	}
+	for _, name := range names {
+		total.Merge(w.results[name], baseline)
+	}
+	opts := w.opts
+	opts.Baseline = baseline
+	total.CheckMethods(opts)
+	total.CheckDuplicates(opts)
+	printSummary(os.Stdout, total)
+	w.set(total)
+}